@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
 	"log"
 	"os"
+	"strconv"
 
-	"github.com/sergrom/csv2xls/v3/internal/app"
+	"github.com/sergrom/csv2xls/v3/xls"
 	"github.com/spf13/cobra"
 )
 
@@ -34,7 +39,7 @@ var rootCmd = &cobra.Command{
 			csvDelimiter = delim
 		}
 
-		var title, subject, creator, keywords, description, lastModifiedBy string
+		var title, subject, creator, keywords, description, lastModifiedBy, company, manager, category string
 
 		if title, err = cmd.Flags().GetString("title"); err != nil {
 			log.Fatal(err.Error())
@@ -54,27 +59,548 @@ var rootCmd = &cobra.Command{
 		if lastModifiedBy, err = cmd.Flags().GetString("last-modified-by"); err != nil {
 			log.Fatal(err.Error())
 		}
+		if company, err = cmd.Flags().GetString("company"); err != nil {
+			log.Fatal(err.Error())
+		}
+		if manager, err = cmd.Flags().GetString("manager"); err != nil {
+			log.Fatal(err.Error())
+		}
+		if category, err = cmd.Flags().GetString("category"); err != nil {
+			log.Fatal(err.Error())
+		}
+
+		converter, err := xls.NewCsv2XlsConverter(csvFileName, xlsFileName, csvDelimiter)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		defaultColumnType, err := cmd.Flags().GetString("default-column-type")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		columnType, err := xls.ParseColumnType(defaultColumnType)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithDefaultColumnType(columnType)
+
+		columnTypes, err := cmd.Flags().GetStringArray("column-type")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, columnTypeFlag := range columnTypes {
+			column, value, err := parseColumnFlag(columnTypeFlag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			columnType, err := xls.ParseColumnType(value)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithColumnType(column, columnType)
+		}
+
+		decimalSeparator, err := getRuneFlag(cmd, "decimal-separator")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		thousandsSeparator, err := getRuneFlag(cmd, "thousands-separator")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithNumberSeparators(decimalSeparator, thousandsSeparator)
+
+		if cmd.Flags().Changed("date-layout") {
+			dateLayouts, err := cmd.Flags().GetStringArray("date-layout")
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithDateLayouts(dateLayouts...)
+		}
+
+		if cmd.Flags().Changed("boolean-tokens") {
+			booleanTokens, err := cmd.Flags().GetString("boolean-tokens")
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			trueTokens, falseTokens, err := parseBooleanTokens(booleanTokens)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithBooleanTokens(trueTokens, falseTokens)
+		}
+
+		columnBooleanTokens, err := cmd.Flags().GetStringArray("column-boolean-tokens")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, columnBooleanTokensFlag := range columnBooleanTokens {
+			column, value, err := parseColumnFlag(columnBooleanTokensFlag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			trueTokens, falseTokens, err := parseBooleanTokens(value)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithColumnBooleanTokens(column, trueTokens, falseTokens)
+		}
+
+		formulas, err := cmd.Flags().GetBool("formulas")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithFormulas(formulas)
+
+		sanitize, err := cmd.Flags().GetString("sanitize")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		sanitizePolicy, err := xls.ParseSanitizePolicy(sanitize)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithSanitization(sanitizePolicy)
+
+		headerRows, err := cmd.Flags().GetInt("header-rows")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithHeaderRows(headerRows)
+
+		autoColumnWidths, err := cmd.Flags().GetBool("auto-column-widths")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithAutoColumnWidths(autoColumnWidths)
+
+		maxColumnWidth, err := cmd.Flags().GetInt("max-column-width")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithMaxColumnWidth(maxColumnWidth)
+
+		columnWidths, err := cmd.Flags().GetStringArray("column-width")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, flag := range columnWidths {
+			column, value, err := parseColumnFlag(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			width, err := strconv.Atoi(value)
+			if err != nil {
+				log.Fatalf("invalid column width %q", flag)
+			}
+			converter.WithColumnWidth(column, width)
+		}
+
+		mergedCells, err := cmd.Flags().GetStringArray("merge")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range mergedCells {
+			sheet, r, err := xls.ParseRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithMergedCells(sheet, r)
+		}
+
+		validations, err := cmd.Flags().GetStringArray("validation")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, rule := range validations {
+			sheet, dv, err := xls.ParseDataValidation(rule)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithDataValidation(sheet, dv)
+		}
+
+		protectSheets, err := cmd.Flags().GetBool("protect-sheets")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		sheetPassword, err := cmd.Flags().GetString("sheet-password")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		sheetPermissions, err := cmd.Flags().GetString("sheet-permissions")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if protectSheets || sheetPassword != "" {
+			permissions, err := xls.ParseSheetPermissions(sheetPermissions)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithSheetProtection(xls.AllSheets, sheetPassword, permissions)
+		}
+
+		allowEditRanges, err := cmd.Flags().GetStringArray("allow-edit-range")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, spec := range allowEditRanges {
+			sheet, aer, err := xls.ParseAllowEditRange(spec)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithAllowEditRange(sheet, aer)
+		}
+
+		unlockedCells, err := cmd.Flags().GetStringArray("unlocked-cells")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range unlockedCells {
+			sheet, r, err := xls.ParseRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithUnlockedCells(sheet, r)
+		}
+
+		hiddenFormulas, err := cmd.Flags().GetStringArray("hidden-formulas")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range hiddenFormulas {
+			sheet, r, err := xls.ParseRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithHiddenFormulas(sheet, r)
+		}
+
+		protectWorkbook, err := cmd.Flags().GetBool("protect-workbook")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		workbookPassword, err := cmd.Flags().GetString("workbook-password")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		protectWindows, err := cmd.Flags().GetBool("protect-windows")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if protectWorkbook || workbookPassword != "" || protectWindows {
+			converter.WithWorkbookProtection(workbookPassword, protectWindows)
+		}
+
+		readOnlyRecommended, err := cmd.Flags().GetBool("read-only-recommended")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithReadOnlyRecommended(readOnlyRecommended)
+
+		modifyPassword, err := cmd.Flags().GetString("modify-password")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithModifyPassword(modifyPassword)
+
+		password, err := cmd.Flags().GetString("password")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithPassword(password)
+
+		pageSetups, err := cmd.Flags().GetStringArray("page-setup")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, spec := range pageSetups {
+			sheet, options, err := xls.ParsePageSetup(spec)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithPageSetupOptions(sheet, options...)
+		}
+
+		header, err := cmd.Flags().GetString("header")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if header != "" {
+			hf, err := xls.ParseHeaderFooter(header)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithHeader(xls.AllSheets, hf)
+		}
+
+		footer, err := cmd.Flags().GetString("footer")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if footer != "" {
+			hf, err := xls.ParseHeaderFooter(footer)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithFooter(xls.AllSheets, hf)
+		}
+
+		printAreas, err := cmd.Flags().GetStringArray("print-area")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range printAreas {
+			sheet, r, err := xls.ParseRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithPrintArea(sheet, r)
+		}
+
+		printTitleRows, err := cmd.Flags().GetStringArray("print-title-rows")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range printTitleRows {
+			sheet, first, last, err := xls.ParseRowRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithPrintTitleRows(sheet, first, last)
+		}
+
+		printTitleColumns, err := cmd.Flags().GetStringArray("print-title-columns")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range printTitleColumns {
+			sheet, first, last, err := xls.ParseColumnRange(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithPrintTitleColumns(sheet, first, last)
+		}
+
+		hyperlinks, err := cmd.Flags().GetBool("hyperlinks")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithHyperlinks(hyperlinks)
+
+		hyperlinkColumns, err := cmd.Flags().GetStringArray("hyperlink-column")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, flag := range hyperlinkColumns {
+			column, value, err := parseColumnFlag(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			targetColumn, err := xls.ParseColumn(value)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithHyperlinkColumn(column, targetColumn)
+		}
+
+		noteColumns, err := cmd.Flags().GetStringArray("note-column")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, flag := range noteColumns {
+			column, value, err := parseColumnFlag(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			noteColumn, err := xls.ParseColumn(value)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithNoteColumn(column, noteColumn)
+		}
+
+		notesFileName, err := cmd.Flags().GetString("notes-file")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if notesFileName != "" {
+			if err := readNotesFile(converter, notesFileName); err != nil {
+				log.Fatal(err.Error())
+			}
+		}
+
+		images, err := cmd.Flags().GetStringArray("image")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, flag := range images {
+			sheet, img, err := parseImageFlag(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithImage(sheet, img)
+		}
+
+		charts, err := cmd.Flags().GetStringArray("chart")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, flag := range charts {
+			sheet, chart, err := xls.ParseChart(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithChart(sheet, chart)
+		}
+
+		autoFilter, err := cmd.Flags().GetBool("autofilter")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithAutoFilter(autoFilter)
+
+		freezePanes, err := cmd.Flags().GetStringArray("freeze-panes")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		for _, ref := range freezePanes {
+			sheet, row, column, err := xls.ParseCell(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithFreezePanes(sheet, row, column)
+		}
 
-		converter, err := app.NewCsv2XlsConverter(csvFileName, xlsFileName, csvDelimiter)
+		splitPanes, err := cmd.Flags().GetStringArray("split-panes")
 		if err != nil {
 			log.Fatal(err.Error())
 		}
+		for _, ref := range splitPanes {
+			sheet, row, column, err := xls.ParseCell(ref)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithSplitPanes(sheet, row, column)
+		}
+
+		var dateFormat, dateTimeFormat string
+		if dateFormat, err = cmd.Flags().GetString("date-format"); err != nil {
+			log.Fatal(err.Error())
+		}
+		if dateTimeFormat, err = cmd.Flags().GetString("datetime-format"); err != nil {
+			log.Fatal(err.Error())
+		}
+		converter.WithDateFormats(dateFormat, dateTimeFormat)
 
-		err = converter.
+		converter.
 			WithTitle(title).
 			WithSubject(subject).
 			WithDescription(description).
 			WithKeywords(keywords).
 			WithCreator(creator).
 			WithLastModifiedBy(lastModifiedBy).
-			Convert()
+			WithCompany(company).
+			WithManager(manager).
+			WithCategory(category)
 
+		properties, err := cmd.Flags().GetStringArray("property")
 		if err != nil {
 			log.Fatal(err.Error())
 		}
+		for _, flag := range properties {
+			property, err := xls.ParseProperty(flag)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			converter.WithCustomProperty(property.Name, property.Value)
+		}
+
+		if csvFileName == "-" || xlsFileName == "-" {
+			err = convertStream(converter, csvFileName, xlsFileName)
+		} else {
+			err = converter.Convert()
+		}
+
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		sanitizeReport, err := cmd.Flags().GetString("sanitize-report")
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if sanitizeReport != "" {
+			if err = writeSanitizationReport(sanitizeReport, converter.SanitizationReport()); err != nil {
+				log.Fatal(err.Error())
+			}
+		}
 	},
 }
 
+// convertStream runs the conversion where "-" stands for stdin or stdout
+func convertStream(converter *xls.Csv2XlsConverter, csvFileName, xlsFileName string) error {
+	var in io.Reader = os.Stdin
+	if csvFileName != "-" {
+		f, err := os.Open(csvFileName)
+		if err != nil {
+			return &xls.CsvReadError{FileName: csvFileName, Err: err}
+		}
+		defer f.Close()
+		in = f
+	}
+
+	if xlsFileName == "-" {
+		w := bufio.NewWriter(os.Stdout)
+		if err := converter.ConvertStream(context.Background(), in, w); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	f, err := os.Create(xlsFileName)
+	if err != nil {
+		return &xls.XlsWriteError{FileName: xlsFileName, Err: err}
+	}
+
+	// The partial file is removed when the conversion fails, it is closed first
+	w := bufio.NewWriter(f)
+	err = converter.ConvertStream(context.Background(), in, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = &xls.XlsWriteError{FileName: xlsFileName, Err: closeErr}
+	}
+	if err != nil {
+		_ = os.Remove(xlsFileName)
+	}
+
+	return err
+}
+
+// writeSanitizationReport writes the sanitized values as csv with the row, column, original and sanitized value
+func writeSanitizationReport(fileName string, report []xls.SanitizedCell) error {
+	var out io.Writer = os.Stderr
+	if fileName != "-" {
+		f, err := os.Create(fileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"row", "column", "original", "sanitized"})
+	for _, cell := range report {
+		_ = w.Write([]string{strconv.Itoa(cell.Row + 1), strconv.Itoa(cell.Column + 1), cell.Original, cell.Sanitized})
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
 // Execute ...
 func Execute() {
 	err := rootCmd.Execute()
@@ -85,11 +611,11 @@ func Execute() {
 
 func init() {
 	// Mandatory parameter
-	rootCmd.Flags().String("csv-file-name", "", `The input csv file you want to convert`)
+	rootCmd.Flags().String("csv-file-name", "", `The input csv file you want to convert, "-" reads from stdin`)
 	_ = rootCmd.MarkFlagRequired("csv-file-name")
 
 	// Mandatory parameter
-	rootCmd.Flags().String("xls-file-name", "", `The output xls file name that will be created`)
+	rootCmd.Flags().String("xls-file-name", "", `The output xls file name that will be created, "-" writes to stdout`)
 	_ = rootCmd.MarkFlagRequired("xls-file-name")
 
 	// Optional parameters:
@@ -100,4 +626,53 @@ func init() {
 	rootCmd.Flags().String("keywords", "", `Optional. The Keywords property of xls file`)
 	rootCmd.Flags().String("description", "", `Optional. The Description property of xls file`)
 	rootCmd.Flags().String("last-modified-by", "", `Optional. The LastModifiedBy property of xls file`)
+	rootCmd.Flags().String("company", "", `Optional. The Company property of xls file`)
+	rootCmd.Flags().String("manager", "", `Optional. The Manager property of xls file`)
+	rootCmd.Flags().String("category", "", `Optional. The Category property of xls file`)
+	rootCmd.Flags().StringArray("property", nil, `Optional. Repeatable. A custom property of xls file as name=value or name:type=value with the type int, float, bool, date or string, without a type the value is a boolean, a number, a date like 2024-01-31 or a string, e.g. "Reviewed=true" or "Batch:string=42"`)
+	rootCmd.Flags().String("default-column-type", "auto", `Optional. How values are written: "auto" detects numbers, dates and booleans, "number", "date" or "boolean" detect only one of them, "text" writes every value as a string`)
+	rootCmd.Flags().StringArray("column-type", nil, `Optional. Repeatable. The type of one column as column=type, e.g. "A=text" or "3=number"`)
+	rootCmd.Flags().String("decimal-separator", ".", `Optional. The decimal separator of numeric values`)
+	rootCmd.Flags().String("thousands-separator", ",", `Optional. The thousands separator of numeric values, empty string disables grouping`)
+	rootCmd.Flags().StringArray("date-layout", nil, `Optional. Repeatable. The Go time layout of date values, e.g. "02.01.2006". Replaces the default ISO-8601 layouts`)
+	rootCmd.Flags().String("boolean-tokens", "TRUE:FALSE", `Optional. The values written as booleans as true-values:false-values, e.g. "TRUE,yes:FALSE,no"`)
+	rootCmd.Flags().StringArray("column-boolean-tokens", nil, `Optional. Repeatable. The boolean values of one column as column=true-values:false-values, e.g. "C=1:0"`)
+	rootCmd.Flags().Bool("formulas", false, `Optional. Write values starting with "=" as formulas, e.g. "=SUM(B2:B10)"`)
+	rootCmd.Flags().String("sanitize", "none", `Optional. What to do with values starting with "=", "+", "-", "@", tab or CR: "none", "escape" with the quote prefix, "strip" the leading "=", "@", tab and CR or "reject" the file`)
+	rootCmd.Flags().Int("header-rows", 0, "Optional. The number of header rows written in bold, frozen and repeated on every worksheet")
+	rootCmd.Flags().Bool("auto-column-widths", true, "Optional. Fit the column widths to the longest values, otherwise the columns are 10 characters wide")
+	rootCmd.Flags().Int("max-column-width", xls.DefaultMaxColumnWidth, "Optional. The maximum width of the fitted columns in characters")
+	rootCmd.Flags().StringArray("column-width", nil, `Optional. Repeatable. The width of one column in characters as column=width, e.g. "A=30"`)
+	rootCmd.Flags().StringArray("merge", nil, `Optional. Repeatable. Merge the cells of a range, e.g. "A1:D1" or "worksheet1!A1:D1" for one worksheet`)
+	rootCmd.Flags().StringArray("validation", nil, `Optional. Repeatable. A data validation rule as range=type:values|option=value, e.g. "B2:B100=list:Open,Closed|error=Pick a status"`)
+	rootCmd.Flags().Bool("protect-sheets", false, "Optional. Protect the worksheets against changes")
+	rootCmd.Flags().String("sheet-password", "", "Optional. The password of the worksheet protection, it turns the protection on")
+	rootCmd.Flags().String("sheet-permissions", xls.DefaultSheetPermissions.String(), `Optional. The comma separated actions allowed on the protected worksheets, e.g. "select-locked-cells,select-unlocked-cells,sort,autofilter"`)
+	rootCmd.Flags().StringArray("allow-edit-range", nil, `Optional. Repeatable. A range of the protected worksheets that stays editable, e.g. "B2:C100|title=Inputs|password=secret"`)
+	rootCmd.Flags().StringArray("unlocked-cells", nil, `Optional. Repeatable. Unlock the csv cells of a range on the protected worksheets, e.g. "B2:C100"`)
+	rootCmd.Flags().StringArray("hidden-formulas", nil, `Optional. Repeatable. Hide the formulas of the csv cells of a range on the protected worksheets, e.g. "D2:D100"`)
+	rootCmd.Flags().Bool("protect-workbook", false, "Optional. Protect the workbook structure, the worksheets cannot be added, removed or renamed")
+	rootCmd.Flags().String("workbook-password", "", "Optional. The password of the workbook structure protection, it turns the protection on")
+	rootCmd.Flags().Bool("protect-windows", false, "Optional. Protect the workbook windows from moving, resizing and closing")
+	rootCmd.Flags().Bool("read-only-recommended", false, "Optional. Make Excel suggest opening the file read-only")
+	rootCmd.Flags().String("modify-password", "", "Optional. The password to modify, without it Excel opens the file read-only")
+	rootCmd.Flags().String("password", "", "Optional. Encrypt the xls file, Excel asks for the password to open it")
+	rootCmd.Flags().StringArray("page-setup", nil, `Optional. Repeatable. The print layout as option=value|..., e.g. "orientation=portrait|paper=a4|fit=1x0" or "worksheet1!center=both" for one worksheet`)
+	rootCmd.Flags().String("header", "", `Optional. The page header as an Excel template with the &L, &C and &R sections, e.g. "&L&A&RPrinted &D"`)
+	rootCmd.Flags().String("footer", "", `Optional. The page footer as an Excel template with the &L, &C and &R sections, e.g. "&CPage &P of &N"`)
+	rootCmd.Flags().StringArray("print-area", nil, `Optional. Repeatable. Print only a range, e.g. "A1:F100" or "worksheet1!A1:F100" for one worksheet`)
+	rootCmd.Flags().StringArray("print-title-rows", nil, `Optional. Repeatable. The rows repeated at the top of every printed page, e.g. "1:2" or "worksheet1!1:2" for one worksheet, the header rows by default`)
+	rootCmd.Flags().StringArray("print-title-columns", nil, `Optional. Repeatable. The columns repeated at the left of every printed page, e.g. "A:B" or "worksheet1!A:B" for one worksheet`)
+	rootCmd.Flags().Bool("hyperlinks", false, "Optional. Turn the URLs and the email addresses into links")
+	rootCmd.Flags().StringArray("hyperlink-column", nil, `Optional. Repeatable. Link the cells of a column to the targets of another column, e.g. "A=F", a target like "#worksheet1!A1" links into the workbook`)
+	rootCmd.Flags().StringArray("note-column", nil, `Optional. Repeatable. Add notes to the cells of a column from another column, e.g. "B=F"`)
+	rootCmd.Flags().String("notes-file", "", `Optional. A JSON file mapping the cells to the note texts, e.g. {"B2": "Check the total", "worksheet1!C5": "Duplicate"}`)
+	rootCmd.Flags().StringArray("chart", nil, `Optional. Repeatable. Embed a column, bar, line, area or pie chart of the data rows into a range, e.g. "H2:P20=line:A:B,C|title=Sales" charts the columns B and C by the categories of the column A`)
+	rootCmd.Flags().StringArray("image", nil, `Optional. Repeatable. Place a PNG or JPEG picture with the top left corner in a cell, e.g. "worksheet!A1=logo.png|width=200|height=60"`)
+	rootCmd.Flags().Bool("autofilter", false, "Optional. Turn on the filter drop-downs in the last header row or in the first row")
+	rootCmd.Flags().StringArray("freeze-panes", nil, `Optional. Repeatable. Freeze the rows above and the columns to the left of a cell, e.g. "B2" or "worksheet1!B2" for one worksheet`)
+	rootCmd.Flags().StringArray("split-panes", nil, `Optional. Repeatable. Split the window above and to the left of a cell, e.g. "B2" or "worksheet1!B2" for one worksheet`)
+	rootCmd.Flags().String("sanitize-report", "", `Optional. The csv file to write the sanitized values into, "-" writes to stderr`)
+	rootCmd.Flags().String("date-format", xls.DefaultDateFormat, `Optional. The Excel number format of dates`)
+	rootCmd.Flags().String("datetime-format", xls.DefaultDateTimeFormat, `Optional. The Excel number format of dates with time of day`)
 }
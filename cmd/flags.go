@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sergrom/csv2xls/v3/xls"
+	"github.com/spf13/cobra"
+)
+
+// parseColumnFlag splits a "column=value" flag value, the column is a letter or a 1-based number
+func parseColumnFlag(flag string) (int, string, error) {
+	parts := strings.SplitN(flag, "=", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf(`invalid value %q, expected column=value`, flag)
+	}
+
+	column, err := xls.ParseColumn(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return column, strings.TrimSpace(parts[1]), nil
+}
+
+// getRuneFlag returns a flag that must be a single character or an empty string
+func getRuneFlag(cmd *cobra.Command, name string) (rune, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return 0, err
+	}
+	if utf8.RuneCountInString(value) > 1 {
+		return 0, fmt.Errorf("%s must be one character string", name)
+	}
+
+	r, _ := utf8.DecodeRuneInString(value)
+	if r == utf8.RuneError {
+		r = 0
+	}
+
+	return r, nil
+}
+
+// parseBooleanTokens splits a "true1,true2:false1,false2" flag value
+func parseBooleanTokens(flag string) ([]string, []string, error) {
+	parts := strings.Split(flag, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, nil, fmt.Errorf(`invalid boolean tokens %q, expected true-values:false-values`, flag)
+	}
+
+	return strings.Split(parts[0], ","), strings.Split(parts[1], ","), nil
+}
+
+// readNotesFile adds the notes of a JSON file like {"B2": "Check the total"} to the converter
+func readNotesFile(converter *xls.Csv2XlsConverter, fileName string) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sheets, notes, err := xls.ReadNotes(f)
+	if err != nil {
+		return fmt.Errorf("cannot read notes file %q: %w", fileName, err)
+	}
+	for i, note := range notes {
+		converter.WithNote(sheets[i], note)
+	}
+
+	return nil
+}
+
+// parseImageFlag reads the picture of a "[worksheet1!]A1=logo.png|width=200|height=60" flag value
+func parseImageFlag(flag string) (int, xls.Image, error) {
+	options := strings.Split(flag, "|")
+	parts := strings.SplitN(options[0], "=", 2)
+	if len(parts) != 2 {
+		return 0, xls.Image{}, fmt.Errorf(`invalid image %q, expected cell=file`, flag)
+	}
+
+	sheet, row, column, err := xls.ParseCell(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, xls.Image{}, err
+	}
+	data, err := ioutil.ReadFile(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, xls.Image{}, err
+	}
+
+	img := xls.Image{Data: data, Row: row, Column: column}
+	for _, option := range options[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return 0, xls.Image{}, fmt.Errorf("invalid image option %q", option)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return 0, xls.Image{}, fmt.Errorf("invalid image option %q", option)
+		}
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "width":
+			img.Width = size
+		case "height":
+			img.Height = size
+		default:
+			return 0, xls.Image{}, fmt.Errorf("invalid image option %q", option)
+		}
+	}
+
+	return sheet, img, nil
+}
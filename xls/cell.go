@@ -0,0 +1,358 @@
+package xls
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultDateLayouts are the Go time layouts of the dates detected by default
+var DefaultDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+var (
+	// DefaultTrueTokens are the csv values written as TRUE by default, they are compared case-insensitively
+	DefaultTrueTokens = []string{"TRUE"}
+	// DefaultFalseTokens are the csv values written as FALSE by default, they are compared case-insensitively
+	DefaultFalseTokens = []string{"FALSE"}
+)
+
+// errorCodes are the Excel error values with their BOOLERR codes
+var errorCodes = map[string]uint8{
+	"#NULL!":  0x00,
+	"#DIV/0!": 0x07,
+	"#VALUE!": 0x0F,
+	"#REF!":   0x17,
+	"#NAME?":  0x1D,
+	"#NUM!":   0x24,
+	"#N/A":    0x2A,
+}
+
+const (
+	// DefaultDateFormat is the Excel number format of the dates without time of day
+	DefaultDateFormat = "yyyy-mm-dd"
+	// DefaultDateTimeFormat is the Excel number format of the dates with time of day
+	DefaultDateTimeFormat = "yyyy-mm-dd hh:mm:ss"
+)
+
+// ColumnType defines how the values of a column are written into the xls file
+type ColumnType int
+
+const (
+	// ColumnTypeAuto detects the type of every value and writes it as a string when nothing else fits
+	ColumnTypeAuto ColumnType = iota
+	// ColumnTypeText writes every value as a string, this is how csv2xls worked before type detection
+	ColumnTypeText
+	// ColumnTypeNumber writes numeric values as numbers and any other value as a string
+	ColumnTypeNumber
+	// ColumnTypeDate writes values matching the date layouts as dates and any other value as a string
+	ColumnTypeDate
+	// ColumnTypeBoolean writes values matching the boolean tokens as booleans and any other value as a string
+	ColumnTypeBoolean
+)
+
+var columnTypeNames = map[ColumnType]string{
+	ColumnTypeAuto:    "auto",
+	ColumnTypeText:    "text",
+	ColumnTypeNumber:  "number",
+	ColumnTypeDate:    "date",
+	ColumnTypeBoolean: "boolean",
+}
+
+// String ...
+func (t ColumnType) String() string {
+	if name, ok := columnTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ColumnType(%d)", int(t))
+}
+
+// ParseColumnType converts a column type name such as "auto" or "text" into a ColumnType
+func ParseColumnType(name string) (ColumnType, error) {
+	for t, n := range columnTypeNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return ColumnTypeAuto, fmt.Errorf("%w: %q", ErrInvalidColumnType, name)
+}
+
+// ParseColumn converts a column letter ("A", "AB") or a 1-based column number ("1", "28") into a 0-based column index
+func ParseColumn(column string) (int, error) {
+	if n, err := strconv.Atoi(column); err == nil {
+		if n < 1 || n > 256 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidColumn, column)
+		}
+		return n - 1, nil
+	}
+
+	idx := 0
+	for _, r := range strings.ToUpper(column) {
+		if r < 'A' || r > 'Z' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidColumn, column)
+		}
+		idx = idx*26 + int(r-'A') + 1
+		if idx > 256 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidColumn, column)
+		}
+	}
+	if idx == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidColumn, column)
+	}
+
+	return idx - 1, nil
+}
+
+// cellKind ...
+type cellKind int
+
+const (
+	cellKindBlank cellKind = iota
+	cellKindString
+	cellKindNumber
+	cellKindBoolean
+	cellKindError
+	cellKindFormula
+)
+
+// cellValue is a csv value converted into the xls cell type
+type cellValue struct {
+	kind   cellKind
+	str    string
+	num    float64
+	format string // Number format code, empty for General
+	code   uint8  // Boolean value or error code
+}
+
+// dateLayout is a Go time layout that is recognized in csv values
+type dateLayout struct {
+	layout   string
+	hasClock bool
+}
+
+func newDateLayout(layout string) dateLayout {
+	midnight := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+	afternoon := time.Date(2006, 1, 2, 13, 47, 29, 123456789, time.UTC)
+
+	return dateLayout{layout, midnight.Format(layout) != afternoon.Format(layout)}
+}
+
+// cellParser detects the types of csv values
+type cellParser struct {
+	defaultType        ColumnType
+	columnTypes        map[int]ColumnType
+	decimalSeparator   rune
+	thousandsSeparator rune
+	dateLayouts        []dateLayout
+	dateFormat         string
+	dateTimeFormat     string
+	booleanTokens      booleanTokens
+	columnBoolTokens   map[int]booleanTokens
+	formulas           bool
+}
+
+// booleanTokens maps the upper-cased csv values to the boolean values
+type booleanTokens map[string]bool
+
+func newBooleanTokens(trueTokens, falseTokens []string) booleanTokens {
+	tokens := make(booleanTokens, len(trueTokens)+len(falseTokens))
+	for _, token := range trueTokens {
+		tokens[strings.ToUpper(token)] = true
+	}
+	for _, token := range falseTokens {
+		tokens[strings.ToUpper(token)] = false
+	}
+
+	return tokens
+}
+
+func (p *cellParser) columnType(columnIdx int) ColumnType {
+	if t, ok := p.columnTypes[columnIdx]; ok {
+		return t
+	}
+	return p.defaultType
+}
+
+func (p *cellParser) parse(columnIdx int, value string) cellValue {
+	if value == "" {
+		return cellValue{kind: cellKindBlank}
+	}
+
+	columnType := p.columnType(columnIdx)
+	if columnType == ColumnTypeText {
+		return cellValue{kind: cellKindString, str: value}
+	}
+
+	if p.formulas && len(value) > 1 && value[0] == '=' {
+		return cellValue{kind: cellKindFormula, str: value[1:]}
+	}
+	if code, ok := errorCodes[strings.ToUpper(value)]; ok {
+		return cellValue{kind: cellKindError, code: code}
+	}
+	// Booleans go before numbers, so "1" and "0" tokens win over the numeric values
+	if columnType == ColumnTypeAuto || columnType == ColumnTypeBoolean {
+		tokens, ok := p.columnBoolTokens[columnIdx]
+		if !ok {
+			tokens = p.booleanTokens
+		}
+		if b, ok := tokens[strings.ToUpper(value)]; ok {
+			cell := cellValue{kind: cellKindBoolean}
+			if b {
+				cell.code = 1
+			}
+			return cell
+		}
+	}
+	if columnType == ColumnTypeAuto || columnType == ColumnTypeNumber {
+		if num, ok := p.parseNumber(value); ok {
+			return cellValue{kind: cellKindNumber, num: num}
+		}
+	}
+	if columnType == ColumnTypeAuto || columnType == ColumnTypeDate {
+		if cell, ok := p.parseDate(value); ok {
+			return cell
+		}
+	}
+
+	return cellValue{kind: cellKindString, str: value}
+}
+
+// parseDate converts a value matching one of the date layouts into an Excel serial date
+func (p *cellParser) parseDate(value string) (cellValue, bool) {
+	for _, layout := range p.dateLayouts {
+		t, err := time.Parse(layout.layout, value)
+		if err != nil {
+			continue
+		}
+
+		serial, ok := excelSerialDate(t)
+		if !ok {
+			continue
+		}
+
+		format := p.dateFormat
+		if layout.hasClock {
+			format = p.dateTimeFormat
+		}
+
+		return cellValue{kind: cellKindNumber, num: serial, format: format}, true
+	}
+
+	return cellValue{}, false
+}
+
+// checkNumberSeparators returns ErrInvalidNumberSeparators if numeric values cannot be parsed unambiguously with the separators
+func checkNumberSeparators(decimalSeparator, thousandsSeparator rune) error {
+	isDigit := func(r rune) bool { return r >= '0' && r <= '9' }
+
+	if decimalSeparator == 0 || isDigit(decimalSeparator) {
+		return fmt.Errorf("%w: decimal separator %q must be a non-digit character", ErrInvalidNumberSeparators, decimalSeparator)
+	}
+	if isDigit(thousandsSeparator) {
+		return fmt.Errorf("%w: thousands separator %q must not be a digit", ErrInvalidNumberSeparators, thousandsSeparator)
+	}
+	if decimalSeparator == thousandsSeparator {
+		return fmt.Errorf("%w: decimal and thousands separators are both %q", ErrInvalidNumberSeparators, decimalSeparator)
+	}
+
+	return nil
+}
+
+// parseNumber accepts values like "42", "-0.5", "12,537,954" or "1.5e3".
+// Values with leading zeros or more than 15 significant digits are not numbers, they would lose data in Excel.
+func (p *cellParser) parseNumber(value string) (float64, bool) {
+	var normalized, digits strings.Builder
+	s := value
+
+	if s[0] == '-' || s[0] == '+' {
+		normalized.WriteByte(s[0])
+		s = s[1:]
+	}
+
+	// integer part with optional thousands groups
+	intDigits, groups, groupLen := 0, 0, 0
+	for s != "" {
+		r, size := utf8.DecodeRuneInString(s)
+		if r >= '0' && r <= '9' {
+			normalized.WriteRune(r)
+			digits.WriteRune(r)
+			intDigits++
+			groupLen++
+		} else if p.thousandsSeparator != 0 && r == p.thousandsSeparator {
+			if (groups == 0 && (groupLen == 0 || groupLen > 3)) || (groups > 0 && groupLen != 3) {
+				return 0, false
+			}
+			groups++
+			groupLen = 0
+		} else {
+			break
+		}
+		s = s[size:]
+	}
+	if groups > 0 && groupLen != 3 {
+		return 0, false
+	}
+
+	if intDigits > 1 && digits.String()[0] == '0' {
+		return 0, false
+	}
+
+	// fraction part
+	fracDigits := 0
+	if r, size := utf8.DecodeRuneInString(s); s != "" && r == p.decimalSeparator {
+		normalized.WriteByte('.')
+		s = s[size:]
+		for s != "" && s[0] >= '0' && s[0] <= '9' {
+			normalized.WriteByte(s[0])
+			digits.WriteByte(s[0])
+			fracDigits++
+			s = s[1:]
+		}
+		if fracDigits == 0 {
+			return 0, false
+		}
+	}
+	if intDigits+fracDigits == 0 {
+		return 0, false
+	}
+
+	// exponent part
+	if s != "" && (s[0] == 'e' || s[0] == 'E') && groups == 0 {
+		normalized.WriteByte('e')
+		s = s[1:]
+		if s != "" && (s[0] == '-' || s[0] == '+') {
+			normalized.WriteByte(s[0])
+			s = s[1:]
+		}
+		expDigits := 0
+		for s != "" && s[0] >= '0' && s[0] <= '9' {
+			normalized.WriteByte(s[0])
+			expDigits++
+			s = s[1:]
+		}
+		if expDigits == 0 {
+			return 0, false
+		}
+	}
+	if s != "" {
+		return 0, false
+	}
+
+	if len(strings.Trim(digits.String(), "0")) > 15 {
+		return 0, false
+	}
+
+	num, err := strconv.ParseFloat(normalized.String(), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return num, true
+}
@@ -0,0 +1,98 @@
+package xls
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		num   float64
+		ok    bool
+	}{
+		{"42", 42, true},
+		{"-0.5", -0.5, true},
+		{"+7", 7, true},
+		{"12,537,954", 12537954, true},
+		{"1,500", 1500, true},
+		{"1.5e3", 1500, true},
+		{"-2E-2", -0.02, true},
+		{"0", 0, true},
+		{"0.25", 0.25, true},
+		{"007", 0, false},
+		{"-01", 0, false},
+		{"1,23", 0, false},
+		{"1234,567", 0, false},
+		{",123", 0, false},
+		{"1,500e3", 0, false},
+		{"1.", 0, false},
+		{".5", 0.5, true},
+		{"1e", 0, false},
+		{"12a", 0, false},
+		{"-", 0, false},
+		{"123456789012345", 123456789012345, true},
+		{"1234567890123456", 0, false},
+		{"1.23456789012345", 1.23456789012345, true},
+		{"1.234567890123456", 0, false},
+		{"100000000000000000000", 1e20, true},
+	}
+
+	p := &cellParser{decimalSeparator: '.', thousandsSeparator: ','}
+	for _, tt := range tests {
+		num, ok := p.parseNumber(tt.value)
+		if ok != tt.ok || num != tt.num {
+			t.Errorf("parseNumber(%q) = %v, %v, want %v, %v", tt.value, num, ok, tt.num, tt.ok)
+		}
+	}
+}
+
+func TestParseNumberSeparators(t *testing.T) {
+	p := &cellParser{decimalSeparator: ',', thousandsSeparator: '.'}
+	if num, ok := p.parseNumber("1.234,5"); !ok || num != 1234.5 {
+		t.Errorf(`parseNumber("1.234,5") = %v, %v, want 1234.5, true`, num, ok)
+	}
+
+	p = &cellParser{decimalSeparator: '.'}
+	if _, ok := p.parseNumber("1,500"); ok {
+		t.Error(`parseNumber("1,500") without a thousands separator is a number`)
+	}
+}
+
+func TestCheckNumberSeparators(t *testing.T) {
+	tests := []struct {
+		decimal, thousands rune
+		valid              bool
+	}{
+		{'.', ',', true},
+		{',', '.', true},
+		{',', 0, true},
+		{',', ',', false},
+		{0, ',', false},
+		{'5', ',', false},
+		{'.', '0', false},
+	}
+
+	for _, tt := range tests {
+		err := checkNumberSeparators(tt.decimal, tt.thousands)
+		if tt.valid && err != nil || !tt.valid && !errors.Is(err, ErrInvalidNumberSeparators) {
+			t.Errorf("checkNumberSeparators(%q, %q) = %v", tt.decimal, tt.thousands, err)
+		}
+	}
+}
+
+func TestParseDateTriesEveryLayout(t *testing.T) {
+	// "1111-11-11" is out of range with the first layout and 2011-11-11 with the second one
+	p := &cellParser{
+		dateLayouts: []dateLayout{newDateLayout("2006-01-02"), newDateLayout("0102-06-01")},
+		dateFormat:  DefaultDateFormat,
+	}
+	cell, ok := p.parseDate("1111-11-11")
+	if !ok || cell.num != 40858 {
+		t.Errorf(`parseDate("1111-11-11") = %v, %v, want 40858, true`, cell.num, ok)
+	}
+
+	if _, ok := p.parseDate("1850-01-01"); ok {
+		t.Error(`parseDate("1850-01-01") before 1900 is a date`)
+	}
+}
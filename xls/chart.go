@@ -0,0 +1,381 @@
+package xls
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ChartType is the kind of a chart
+type ChartType int
+
+const (
+	// ChartColumn shows the series as vertical bars
+	ChartColumn ChartType = iota
+	// ChartBar shows the series as horizontal bars
+	ChartBar
+	// ChartLine shows the series as lines
+	ChartLine
+	// ChartArea shows the series as filled areas
+	ChartArea
+	// ChartPie shows the first series as a pie
+	ChartPie
+)
+
+var chartTypeNames = map[ChartType]string{
+	ChartColumn: "column",
+	ChartBar:    "bar",
+	ChartLine:   "line",
+	ChartArea:   "area",
+	ChartPie:    "pie",
+}
+
+// String ...
+func (t ChartType) String() string {
+	if name, ok := chartTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ChartType(%d)", int(t))
+}
+
+// Chart is a chart of the data rows of a worksheet embedded into the worksheet
+type Chart struct {
+	Type           ChartType
+	Title          string
+	CategoryColumn int   // 0-based column of the category labels
+	SeriesColumns  []int // 0-based columns of the values, the header row has the series names
+	Range          CellRange
+}
+
+// ParseChart converts a chart like "H2:P20=line:A:B,C|title=Sales" into the worksheet index and the chart.
+// The chart is range=type:category column:series columns, where the range is covered by the chart and the type is
+// column, bar, line, area or pie. The only option after "|" is title. The worksheet index is AllSheets when the range has no worksheet name.
+func ParseChart(spec string) (int, Chart, error) {
+	invalid := func() (int, Chart, error) {
+		return 0, Chart{}, fmt.Errorf("%w: %q", ErrInvalidChart, spec)
+	}
+
+	options := strings.Split(spec, "|")
+	parts := strings.SplitN(options[0], "=", 2)
+	if len(parts) != 2 {
+		return invalid()
+	}
+
+	sheet, r, err := ParseRange(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, Chart{}, err
+	}
+	chart := Chart{Range: r}
+
+	args := strings.Split(parts[1], ":")
+	if len(args) != 3 {
+		return invalid()
+	}
+	typeFound := false
+	for t, name := range chartTypeNames {
+		if strings.EqualFold(name, strings.TrimSpace(args[0])) {
+			chart.Type, typeFound = t, true
+		}
+	}
+	if !typeFound {
+		return invalid()
+	}
+	if chart.CategoryColumn, err = ParseColumn(strings.TrimSpace(args[1])); err != nil {
+		return 0, Chart{}, err
+	}
+	for _, column := range strings.Split(args[2], ",") {
+		idx, err := ParseColumn(strings.TrimSpace(column))
+		if err != nil {
+			return 0, Chart{}, err
+		}
+		chart.SeriesColumns = append(chart.SeriesColumns, idx)
+	}
+
+	for _, option := range options[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || !strings.EqualFold(strings.TrimSpace(kv[0]), "title") {
+			return invalid()
+		}
+		chart.Title = kv[1]
+	}
+
+	return sheet, chart, nil
+}
+
+// check returns ErrInvalidChart if the chart cannot be drawn
+func (c Chart) check() error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s %s: %s", ErrInvalidChart, c.Type, c.Range, reason)
+	}
+
+	if _, ok := chartTypeNames[c.Type]; !ok {
+		return invalid("unknown type")
+	}
+	if !c.Range.isValid() {
+		return invalid("range out of the worksheet")
+	}
+	if len(c.SeriesColumns) == 0 {
+		return invalid("no series")
+	}
+	for _, column := range append([]int{c.CategoryColumn}, c.SeriesColumns...) {
+		if column < 0 || column > 255 {
+			return invalid("column out of the worksheet")
+		}
+	}
+	if utf16Length(c.Title) > 255 {
+		return invalid("the title is limited to 255 characters")
+	}
+
+	return nil
+}
+
+// chartRecord writes a chart record, the length is the size of the data
+func chartRecord(buffer *bytes.Buffer, record uint16, data ...interface{}) {
+	buf := new(bytes.Buffer)
+	putVar(buf, data...)
+	putVar(buffer, record, uint16(buf.Len()), buf.Bytes())
+}
+
+// chartBegin and chartEnd enclose the records of a chart object
+func chartBegin(buffer *bytes.Buffer) { chartRecord(buffer, 0x1033) }
+func chartEnd(buffer *bytes.Buffer)   { chartRecord(buffer, 0x1034) }
+
+// chartLineFormat writes the LINEFORMAT record of an automatic line
+func chartLineFormat(buffer *bytes.Buffer) {
+	chartRecord(buffer, 0x1007, uint32(0), uint16(0), int16(0), uint16(0x0009), uint16(0x004D))
+}
+
+// chartAreaFormat writes the AREAFORMAT record of an automatic fill
+func chartAreaFormat(buffer *bytes.Buffer) {
+	chartRecord(buffer, 0x100A, uint32(0x00FFFFFF), uint32(0), uint16(1), uint16(0x0001), uint16(0x004E), uint16(0x004D))
+}
+
+// chartFrame writes the FRAME of the chart area or of the plot area
+func chartFrame(buffer *bytes.Buffer) {
+	chartRecord(buffer, 0x1032, uint16(0), uint16(0x0003)) // Automatic size and position
+	chartBegin(buffer)
+	chartLineFormat(buffer)
+	chartAreaFormat(buffer)
+	chartEnd(buffer)
+}
+
+// chartPos writes the POS record
+func chartPos(buffer *bytes.Buffer, mdTopLt, mdBotRt uint16, x1, y1, x2, y2 int32) {
+	chartRecord(buffer, 0x104F, mdTopLt, mdBotRt, x1, y1, x2, y2)
+}
+
+// chartAI writes the BRAI record linking a part of a series to the cells, an empty rgce is an automatic link
+func chartAI(buffer *bytes.Buffer, id uint8, rgce []byte) {
+	var rt uint8 = 0x01 // Text or value entered directly
+	if len(rgce) != 0 {
+		rt = 0x02 // Reference to the cells
+	}
+	chartRecord(buffer, 0x1051, id, rt, uint16(0), uint16(0), uint16(len(rgce)), rgce)
+}
+
+// chartText writes the TEXT record of a centered label with the grbit options
+func chartText(buffer *bytes.Buffer, grbit uint16) {
+	chartRecord(buffer, 0x1025,
+		uint8(2), uint8(2), // Centered
+		uint16(1),                              // Transparent background
+		uint32(0),                              // Black text
+		int32(0), int32(0), int32(0), int32(0), // Position
+		grbit, uint16(0x004D), uint16(0), uint16(0), // Options, text color, label position, rotation
+	)
+}
+
+// chartAxis writes an AXIS of the category or the value axis
+func chartAxis(buffer *bytes.Buffer, value bool) {
+	var wType uint16 // Category axis
+	if value {
+		wType = 1
+	}
+	chartRecord(buffer, 0x101D, wType, make([]byte, 16))
+	chartBegin(buffer)
+	if value {
+		// VALUERANGE: automatic minimum, maximum, units and crossing point
+		chartRecord(buffer, 0x101F, float64(0), float64(0), float64(0), float64(0), float64(0), uint16(0x011F))
+	} else {
+		// CATSERRANGE: the axes cross at the first category, every category is labeled
+		chartRecord(buffer, 0x1020, uint16(1), uint16(1), uint16(1), uint16(0x0001))
+		// AXCEXT: automatic category axis
+		chartRecord(buffer, 0x1062, uint16(0), uint16(0), uint16(1), uint16(0), uint16(1), uint16(0), uint16(0), uint16(0), uint16(0x00EF))
+	}
+	// TICK: major marks outside, labels next to the axis
+	chartRecord(buffer, 0x101E, uint8(2), uint8(0), uint8(3), uint8(1), uint32(0), make([]byte, 16), uint16(0x0023), uint16(0x004D), uint16(0))
+	if value {
+		// AXISLINEFORMAT: major gridlines
+		chartRecord(buffer, 0x1021, uint16(1))
+		chartLineFormat(buffer)
+	}
+	chartEnd(buffer)
+}
+
+// chartShape returns the shape holding the chart, the OBJ record of the shape is followed by the chart substream
+func (ws *worksheet) chartShape(sheetIdx int, chart Chart) drawingShape {
+	r := chart.Range
+	return drawingShape{
+		shapeType: 201, // Host control
+		flags:     0x0A00,
+		properties: []escherProperty{
+			{0x007F, 0x01040104}, // Lock against grouping
+			{0x00BF, 0x00080008}, // Fit text to shape
+			{0x0181, 0x0800004E}, // Fill color
+			{0x0183, 0x0800004D}, // Fill back color
+			{0x01BF, 0x00110010}, // No fill hit test
+			{0x01C0, 0x0800004D}, // Line color
+			{0x01FF, 0x00080008}, // Line
+			{0x023F, 0x00020000}, // No shadow
+			{0x03BF, 0x00080000}, // Printed
+		},
+		anchor: clientAnchor{uint16(r.FirstColumn), 0, uint16(r.FirstRow), 0, uint16(r.LastColumn), 1023, uint16(r.LastRow), 255},
+		obj: func(buffer *bytes.Buffer, objID uint16) {
+			objChart(buffer, objID)
+			ws.writeChart(buffer, sheetIdx, chart)
+		},
+	}
+}
+
+// objChart writes the OBJ record of a chart
+func objChart(buffer *bytes.Buffer, objID uint16) {
+	var record uint16 = 0x005D // Record identifier
+	var length uint16 = 0x001A // Bytes to follow
+
+	putVar(buffer, record, length)
+
+	// ftCmo: chart
+	putVar(buffer, uint16(0x0015), uint16(0x0012), uint16(0x0005), objID, uint16(0x6011), uint32(0), uint32(0), uint32(0))
+	// ftEnd
+	putVar(buffer, uint16(0x0000), uint16(0x0000))
+}
+
+// writeChart writes the chart substream of the chart of the worksheet with 0-based index sheetIdx
+func (ws *worksheet) writeChart(buffer *bytes.Buffer, sheetIdx int, chart Chart) {
+	// The data rows of the worksheet, the last header row has the series names
+	firstRow, lastRow := ws.HeaderRows, max(len(ws.Grid)-1, ws.HeaderRows)
+	points := uint16(lastRow - firstRow + 1)
+	series := chart.SeriesColumns
+	if chart.Type == ChartPie {
+		series = series[:1]
+	}
+
+	// BOF of a chart
+	putVar(buffer, uint16(0x0809), uint16(0x0010), uint16(0x0600), uint16(0x0020), uint16(0x0DBB), uint16(0x07CC))
+	putVar(buffer, uint32(0x000100D1), uint32(0x00000406))
+
+	// Page setup of a printed chart
+	chartRecord(buffer, 0x0014)
+	chartRecord(buffer, 0x0015)
+	chartRecord(buffer, 0x0083, uint16(0))
+	chartRecord(buffer, 0x0084, uint16(0))
+	chartRecord(buffer, 0x00A1, uint16(0), uint16(100), uint16(1), uint16(1), uint16(1), uint16(0x0044), uint16(0), uint16(0), 0.3, 0.3, uint16(1))
+	chartRecord(buffer, 0x0033, uint16(3)) // PRINTSIZE: as it is shown
+	chartRecord(buffer, 0x1001, uint16(0)) // UNITS
+
+	// CHART: the position and size in points, the anchor of the shape wins
+	width, height := 0, 0
+	for col := chart.Range.FirstColumn; col <= chart.Range.LastColumn; col++ {
+		width += ws.columnPixels(col)
+	}
+	height = (chart.Range.LastRow - chart.Range.FirstRow + 1) * rowHeightPixels
+	chartRecord(buffer, 0x1002, uint32(0), uint32(0), uint32(width*3/4)<<16, uint32(height*3/4)<<16)
+	chartBegin(buffer)
+
+	chartRecord(buffer, 0x1064, uint32(0x00010000), uint32(0x00010000)) // PLOTGROWTH
+	chartFrame(buffer)
+
+	for i, column := range series {
+		// SERIES: text categories, numeric values
+		chartRecord(buffer, 0x1003, uint16(3), uint16(1), points, points, uint16(1), uint16(0))
+		chartBegin(buffer)
+
+		var name []byte
+		if ws.HeaderRows > 0 {
+			ref := new(bytes.Buffer)
+			putVar(ref, uint8(ptgRef3d), uint16(sheetIdx), uint16(ws.HeaderRows-1), uint16(column))
+			name = ref.Bytes()
+		}
+		chartAI(buffer, 0, name)
+		chartAI(buffer, 1, area3d(sheetIdx, CellRange{firstRow, column, lastRow, column}))
+		chartAI(buffer, 2, area3d(sheetIdx, CellRange{firstRow, chart.CategoryColumn, lastRow, chart.CategoryColumn}))
+		chartAI(buffer, 3, nil)
+
+		// DATAFORMAT of all the points of the series
+		chartRecord(buffer, 0x1006, uint16(0xFFFF), uint16(i), uint16(i), uint16(0))
+		chartBegin(buffer)
+		chartRecord(buffer, 0x105F, uint8(0), uint8(0)) // CHART3DBARSHAPE
+		chartEnd(buffer)
+
+		chartRecord(buffer, 0x1045, uint16(0)) // SERTOCRT: the series belongs to the first chart group
+		chartEnd(buffer)
+	}
+
+	chartRecord(buffer, 0x1044, uint16(0x000E), uint8(0), uint8(0)) // SHTPROPS: plot the visible cells, gaps for the empty cells
+	chartRecord(buffer, 0x1046, uint16(1))                          // AXESUSED
+
+	// AXISPARENT: the primary axes with the plot area
+	chartRecord(buffer, 0x1041, uint16(0), int32(0x00F8), int32(0x01F5), int32(0x0E7F), int32(0x0B36))
+	chartBegin(buffer)
+	chartPos(buffer, 2, 2, 0x008C, 0x01AA, 0x0EEA, 0x0C52)
+	if chart.Type != ChartPie {
+		chartAxis(buffer, false)
+		chartAxis(buffer, true)
+		chartRecord(buffer, 0x1035) // PLOTAREA
+		chartFrame(buffer)
+	}
+
+	// CHARTFORMAT: the chart group, the points of a pie are varied by color
+	var varied uint16
+	if chart.Type == ChartPie {
+		varied = 0x0001
+	}
+	chartRecord(buffer, 0x1014, make([]byte, 16), varied, uint16(0))
+	chartBegin(buffer)
+	switch chart.Type {
+	case ChartColumn:
+		chartRecord(buffer, 0x1017, int16(0), uint16(150), uint16(0))
+	case ChartBar:
+		chartRecord(buffer, 0x1017, int16(0), uint16(150), uint16(0x0001)) // Horizontal bars
+	case ChartLine:
+		chartRecord(buffer, 0x1018, uint16(0))
+	case ChartArea:
+		chartRecord(buffer, 0x101A, uint16(0))
+	case ChartPie:
+		chartRecord(buffer, 0x1019, uint16(0), uint16(0), uint16(0x0002))
+	}
+	chartRecord(buffer, 0x1022, make([]byte, 10)) // CRTLINK
+
+	// LEGEND on the right
+	chartRecord(buffer, 0x1015, int32(0x05F9), int32(0x0EE9), int32(0x047D), int32(0x009C), uint8(3), uint8(1), uint16(0x000F))
+	chartBegin(buffer)
+	chartPos(buffer, 5, 2, 0x05F9, 0x0EE9, 0, 0)
+	chartText(buffer, 0x00B1) // Generated label
+	chartBegin(buffer)
+	chartPos(buffer, 2, 2, 0, 0, 0, 0)
+	chartAI(buffer, 0, nil)
+	chartEnd(buffer)
+	chartEnd(buffer)
+
+	chartEnd(buffer) // CHARTFORMAT
+	chartEnd(buffer) // AXISPARENT
+
+	if chart.Title != "" {
+		chartText(buffer, 0x0081)
+		chartBegin(buffer)
+		chartPos(buffer, 2, 2, 0, 0, 0, 0)
+		chartAI(buffer, 0, nil)
+		chartRecord(buffer, 0x100D, uint16(0), []byte(utf8toBIFF8UnicodeShort(chart.Title))) // SERIESTEXT
+		chartRecord(buffer, 0x1027, uint16(1), uint16(0), uint16(0))                         // OBJECTLINK: chart title
+		chartEnd(buffer)
+	}
+
+	chartEnd(buffer) // CHART
+
+	// DIMENSIONS and the indexes of the cached values, the values are read from the cells
+	chartRecord(buffer, 0x0200, uint32(0), uint32(points), uint16(0), uint16(len(series)), uint16(0))
+	for i := uint16(1); i <= 3; i++ {
+		chartRecord(buffer, 0x1065, i)
+	}
+
+	putVar(buffer, uint16(0x000A), uint16(0)) // EOF
+}
@@ -0,0 +1,184 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// chartTestRecord is a record of a chart substream
+type chartTestRecord struct {
+	id   uint16
+	data []byte
+}
+
+// readChartRecords splits the chart substream into the records
+func readChartRecords(t *testing.T, stream []byte) []chartTestRecord {
+	records := make([]chartTestRecord, 0)
+	for len(stream) > 0 {
+		if len(stream) < 4 {
+			t.Fatalf("truncated record header % x", stream)
+		}
+		id, length := binary.LittleEndian.Uint16(stream), int(binary.LittleEndian.Uint16(stream[2:]))
+		if len(stream) < 4+length {
+			t.Fatalf("record 0x%04X overflows the substream", id)
+		}
+		records = append(records, chartTestRecord{id, stream[4 : 4+length]})
+		stream = stream[4+length:]
+	}
+	return records
+}
+
+func TestWriteChart(t *testing.T) {
+	ws := &worksheet{
+		Grid:       [][]string{{"Month", "Sales", "Costs"}, {"Jan", "1", "2"}, {"Feb", "3", "4"}, {"Mar", "5", "6"}},
+		HeaderRows: 1,
+	}
+	chartRange := CellRange{FirstRow: 1, FirstColumn: 4, LastRow: 10, LastColumn: 8}
+	const sheetIdx = 2 // The third worksheet, e.g. the second part of a split csv file
+
+	tests := []struct {
+		chart  Chart
+		series []int
+	}{
+		{Chart{Type: ChartColumn, Title: "Sales", CategoryColumn: 0, SeriesColumns: []int{1, 2}, Range: chartRange}, []int{1, 2}},
+		{Chart{Type: ChartLine, CategoryColumn: 0, SeriesColumns: []int{2}, Range: chartRange}, []int{2}},
+		{Chart{Type: ChartPie, CategoryColumn: 0, SeriesColumns: []int{2, 1}, Range: chartRange}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		buffer := new(bytes.Buffer)
+		ws.writeChart(buffer, sheetIdx, tt.chart)
+		records := readChartRecords(t, buffer.Bytes())
+
+		first, last := records[0], records[len(records)-1]
+		if first.id != 0x0809 || binary.LittleEndian.Uint16(first.data[2:]) != 0x0020 {
+			t.Errorf("%s: first record 0x%04X % x, want the BOF of a chart", tt.chart.Type, first.id, first.data)
+		}
+		if last.id != 0x000A {
+			t.Errorf("%s: last record 0x%04X, want EOF", tt.chart.Type, last.id)
+		}
+
+		depth, series, axes, pies := 0, make([]int, 0), 0, 0
+		for i, r := range records {
+			switch r.id {
+			case 0x0809, 0x000A:
+				if i != 0 && i != len(records)-1 {
+					t.Errorf("%s: BOF or EOF at record %d", tt.chart.Type, i)
+				}
+			case 0x1033: // BEGIN
+				depth++
+			case 0x1034: // END
+				if depth--; depth < 0 {
+					t.Fatalf("%s: END without BEGIN at record %d", tt.chart.Type, i)
+				}
+			case 0x1003: // SERIES
+				if points := binary.LittleEndian.Uint16(r.data[4:]); points != 3 {
+					t.Errorf("%s: SERIES has %d values, want 3", tt.chart.Type, points)
+				}
+			case 0x1051: // BRAI
+				series = append(series, checkChartAI(t, tt.chart, r.data, sheetIdx, ws.HeaderRows, len(ws.Grid)-1)...)
+			case 0x101D: // AXIS
+				axes++
+			case 0x1019: // PIE
+				pies++
+			}
+		}
+		if depth != 0 {
+			t.Errorf("%s: %d BEGIN records without END", tt.chart.Type, depth)
+		}
+
+		if len(series) != len(tt.series) {
+			t.Fatalf("%s: series columns %v, want %v", tt.chart.Type, series, tt.series)
+		}
+		for i := range series {
+			if series[i] != tt.series[i] {
+				t.Errorf("%s: series columns %v, want %v", tt.chart.Type, series, tt.series)
+			}
+		}
+
+		if tt.chart.Type == ChartPie && (axes != 0 || pies != 1) {
+			t.Errorf("%s: %d AXIS and %d PIE records, want no axes and one PIE", tt.chart.Type, axes, pies)
+		}
+		if tt.chart.Type != ChartPie && (axes != 2 || pies != 0) {
+			t.Errorf("%s: %d AXIS and %d PIE records, want two axes and no PIE", tt.chart.Type, axes, pies)
+		}
+	}
+}
+
+// checkChartAI checks the references of a BRAI record to the worksheet, it returns the column of the series values
+func checkChartAI(t *testing.T, chart Chart, data []byte, sheetIdx, firstRow, lastRow int) []int {
+	id, rt := data[0], data[1]
+	rgce := data[8:]
+	if int(binary.LittleEndian.Uint16(data[6:])) != len(rgce) {
+		t.Fatalf("%s: BRAI %d formula length %d, want %d", chart.Type, id, binary.LittleEndian.Uint16(data[6:]), len(rgce))
+	}
+	if rt != 0x02 {
+		if len(rgce) != 0 {
+			t.Errorf("%s: BRAI %d without a reference has a formula", chart.Type, id)
+		}
+		return nil
+	}
+
+	if xti := int(binary.LittleEndian.Uint16(rgce[1:])); xti != sheetIdx {
+		t.Errorf("%s: BRAI %d refers to the worksheet %d, want %d", chart.Type, id, xti, sheetIdx)
+	}
+
+	switch id {
+	case 0: // The series name in the last header row
+		if rgce[0] != ptgRef3d || int(binary.LittleEndian.Uint16(rgce[3:])) != firstRow-1 {
+			t.Errorf("%s: BRAI name % x", chart.Type, rgce)
+		}
+	case 1, 2: // The values and the categories in the data rows
+		rows := [2]int{int(binary.LittleEndian.Uint16(rgce[3:])), int(binary.LittleEndian.Uint16(rgce[5:]))}
+		columns := [2]int{int(binary.LittleEndian.Uint16(rgce[7:])), int(binary.LittleEndian.Uint16(rgce[9:]))}
+		if rgce[0] != ptgArea3d || rows != [2]int{firstRow, lastRow} || columns[0] != columns[1] {
+			t.Errorf("%s: BRAI %d % x", chart.Type, id, rgce)
+		}
+		if id == 2 && columns[0] != chart.CategoryColumn {
+			t.Errorf("%s: categories in the column %d, want %d", chart.Type, columns[0], chart.CategoryColumn)
+		}
+		if id == 1 {
+			return []int{columns[0]}
+		}
+	default:
+		t.Errorf("%s: BRAI %d refers to the cells", chart.Type, id)
+	}
+	return nil
+}
+
+func TestChartTitle(t *testing.T) {
+	chart := Chart{Type: ChartColumn, SeriesColumns: []int{1}, Range: CellRange{FirstRow: 1, LastRow: 10, LastColumn: 5}}
+
+	// 127 surrogate pairs are 254 UTF-16 characters, one more pair makes 256
+	chart.Title = strings.Repeat("😀", 127)
+	if err := chart.check(); err != nil {
+		t.Errorf("check() of 254 UTF-16 characters = %v", err)
+	}
+	chart.Title += "😀"
+	if err := chart.check(); !errors.Is(err, ErrInvalidChart) {
+		t.Errorf("check() of 256 UTF-16 characters = %v, want ErrInvalidChart", err)
+	}
+
+	chart.Title = "Sales 😀"
+	ws := &worksheet{Grid: [][]string{{"Month", "Sales"}, {"Jan", "1"}}, HeaderRows: 1}
+	buffer := new(bytes.Buffer)
+	ws.writeChart(buffer, 0, chart)
+
+	found := false
+	for _, r := range readChartRecords(t, buffer.Bytes()) {
+		if r.id != 0x100D { // SERIESTEXT
+			continue
+		}
+		found = true
+		// The text identifier, the string length, the option flags and the UTF-16 characters
+		if cch := int(r.data[2]); cch != 8 || len(r.data) != 4+2*cch {
+			t.Errorf("SERIESTEXT has %d characters in %d bytes, want 8", cch, len(r.data))
+		}
+	}
+	if !found {
+		t.Error("no SERIESTEXT record of the title")
+	}
+}
@@ -0,0 +1,1624 @@
+// Package xls converts csv data into the Excel 97-2003 (BIFF8) xls format.
+package xls
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	olePpsTypeRoot   = 5
+	olePpsTypeDir    = 1
+	olePpsTypeFile   = 2
+	oleDataSizeSmall = 0x1000
+	oleLongIntSize   = 4
+	olePpsSize       = 0x80
+)
+
+// Csv2XlsConverter converts a csv file into an xls file. Use NewCsv2XlsConverter to create it.
+type Csv2XlsConverter struct {
+	csvFileName    string
+	xlsFileName    string
+	csvDelimiter   rune
+	title          string
+	subject        string
+	creator        string
+	keywords       string
+	description    string
+	lastModifiedBy string
+	company        string
+	manager        string
+	category       string
+	properties     []CustomProperty
+
+	defaultColumnType  ColumnType
+	columnTypes        map[int]ColumnType
+	decimalSeparator   rune
+	thousandsSeparator rune
+	dateLayouts        []string
+	dateFormat         string
+	dateTimeFormat     string
+	trueTokens         []string
+	falseTokens        []string
+	columnBoolTokens   map[int][2][]string
+	formulas           bool
+	sanitizePolicy     SanitizePolicy
+	sanitizeReport     []SanitizedCell
+	headerRows         int
+	panes              map[int]pane
+	autoFilter         bool
+	columnWidths       map[int]int
+	mergedCells        map[int][]CellRange
+	hyperlinks         bool
+	linkColumns        map[int]int
+	noteColumns        map[int]int
+	notes              map[int][]Note
+	images             map[int][]Image
+	charts             map[int][]Chart
+	dataValidations    map[int][]DataValidation
+	sheetProtections   map[int]sheetProtection
+	allowEditRanges    map[int][]AllowEditRange
+	cellProtections    map[int][]cellProtection
+	workbookProtection *workbookProtection
+	fileSharing        *fileSharing
+	password           string
+	pageSetups         map[int][]PageSetupOption
+	headers            map[int]HeaderFooter
+	footers            map[int]HeaderFooter
+	printAreas         map[int]CellRange
+	printTitleRows     map[int]lineRange
+	printTitleColumns  map[int]lineRange
+	autoColumnWidths   bool
+	maxColumnWidth     int
+}
+
+type dataSectionItem struct {
+	summary    uint32
+	offset     uint32
+	sType      uint32
+	dataInt    uint32
+	dataString string
+	dataLength uint32
+}
+
+// NewCsv2XlsConverter creates a converter of csvFileName into xlsFileName.
+// It returns ErrInvalidDelimiter if csvDelimiter is longer than one character.
+func NewCsv2XlsConverter(csvFileName, xlsFileName, csvDelimiter string) (*Csv2XlsConverter, error) {
+	if utf8.RuneCountInString(csvDelimiter) > 1 {
+		return nil, ErrInvalidDelimiter
+	}
+
+	csvDelimiterDecoded, _ := utf8.DecodeRuneInString(csvDelimiter)
+
+	return &Csv2XlsConverter{
+		csvFileName:        csvFileName,
+		xlsFileName:        xlsFileName,
+		csvDelimiter:       csvDelimiterDecoded,
+		columnTypes:        make(map[int]ColumnType),
+		decimalSeparator:   '.',
+		thousandsSeparator: ',',
+		dateLayouts:        DefaultDateLayouts,
+		dateFormat:         DefaultDateFormat,
+		dateTimeFormat:     DefaultDateTimeFormat,
+		trueTokens:         DefaultTrueTokens,
+		falseTokens:        DefaultFalseTokens,
+		columnBoolTokens:   make(map[int][2][]string),
+		panes:              make(map[int]pane),
+		columnWidths:       make(map[int]int),
+		mergedCells:        make(map[int][]CellRange),
+		linkColumns:        make(map[int]int),
+		noteColumns:        make(map[int]int),
+		notes:              make(map[int][]Note),
+		images:             make(map[int][]Image),
+		charts:             make(map[int][]Chart),
+		dataValidations:    make(map[int][]DataValidation),
+		sheetProtections:   make(map[int]sheetProtection),
+		allowEditRanges:    make(map[int][]AllowEditRange),
+		cellProtections:    make(map[int][]cellProtection),
+		pageSetups:         make(map[int][]PageSetupOption),
+		headers:            make(map[int]HeaderFooter),
+		footers:            make(map[int]HeaderFooter),
+		printAreas:         make(map[int]CellRange),
+		printTitleRows:     make(map[int]lineRange),
+		printTitleColumns:  make(map[int]lineRange),
+		autoColumnWidths:   true,
+		maxColumnWidth:     DefaultMaxColumnWidth,
+	}, nil
+}
+
+// NewCsv2XlsStreamConverter creates a converter that is not bound to files, use ConvertStream with it.
+// It returns ErrInvalidDelimiter if csvDelimiter is longer than one character.
+func NewCsv2XlsStreamConverter(csvDelimiter string) (*Csv2XlsConverter, error) {
+	return NewCsv2XlsConverter("", "", csvDelimiter)
+}
+
+// Convert reads the csv file and writes the xls file.
+// The xls file is removed if the conversion fails.
+func (c *Csv2XlsConverter) Convert() error {
+	in, err := os.Open(c.csvFileName)
+	if err != nil {
+		return &CsvReadError{c.csvFileName, err}
+	}
+	defer in.Close()
+
+	f, err := os.Create(c.xlsFileName)
+	if err != nil {
+		return &XlsWriteError{c.xlsFileName, err}
+	}
+
+	w := bufio.NewWriter(f)
+	err = c.ConvertStream(context.Background(), in, w)
+	if err == nil {
+		if err = w.Flush(); err == nil {
+			// Issue a `Sync` to flush writes to stable storage.
+			err = f.Sync()
+		}
+		if err != nil {
+			err = &XlsWriteError{c.xlsFileName, err}
+		}
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = &XlsWriteError{c.xlsFileName, closeErr}
+	}
+	if err != nil {
+		_ = os.Remove(c.xlsFileName)
+		return err
+	}
+
+	return nil
+}
+
+// ConvertStream reads csv data from r and writes the xls data into w.
+// The conversion stops with ctx.Err() when ctx is cancelled.
+func (c *Csv2XlsConverter) ConvertStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	var CreatedAtInt int64 = time.Now().Unix()
+	var ModifiedAtInt int64 = time.Now().Unix()
+
+	if c.headerRows < 0 || c.headerRows >= 65535 {
+		return ErrInvalidHeaderRows
+	}
+
+	if err := checkNumberSeparators(c.decimalSeparator, c.thousandsSeparator); err != nil {
+		return err
+	}
+
+	if c.formulas && c.sanitizePolicy != SanitizeNone {
+		return fmt.Errorf("%w: %s", ErrSanitizeFormulas, c.sanitizePolicy)
+	}
+
+	if c.maxColumnWidth < 1 || c.maxColumnWidth > maxColumnWidth {
+		return ErrInvalidColumnWidth
+	}
+	for _, width := range c.columnWidths {
+		if width < 0 || width > maxColumnWidth {
+			return ErrInvalidColumnWidth
+		}
+	}
+	for column, target := range c.linkColumns {
+		if column < 0 || column > 255 || target < 0 || target > 255 {
+			return ErrInvalidColumn
+		}
+	}
+	for column, noteColumn := range c.noteColumns {
+		if column < 0 || column > 255 || noteColumn < 0 || noteColumn > 255 {
+			return ErrInvalidColumn
+		}
+	}
+	for _, notes := range c.notes {
+		for _, note := range notes {
+			if err := note.check(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, images := range c.images {
+		for _, img := range images {
+			if _, err := newSheetImage(img); err != nil {
+				return err
+			}
+		}
+	}
+	for _, property := range c.properties {
+		if err := property.check(); err != nil {
+			return err
+		}
+	}
+	for _, charts := range c.charts {
+		for _, chart := range charts {
+			if err := chart.check(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, ranges := range c.mergedCells {
+		for _, r := range ranges {
+			if !r.isValid() {
+				return fmt.Errorf("%w: %s", ErrInvalidRange, r)
+			}
+		}
+	}
+	if utf8.RuneCountInString(c.password) > maxOpenPasswordLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidPassword, maxOpenPasswordLength)
+	}
+	if c.workbookProtection != nil {
+		if err := checkPassword(c.workbookProtection.password); err != nil {
+			return err
+		}
+	}
+	if c.fileSharing != nil {
+		if err := checkPassword(c.fileSharing.modifyPassword); err != nil {
+			return err
+		}
+	}
+	for _, p := range c.sheetProtections {
+		if err := checkPassword(p.password); err != nil {
+			return err
+		}
+	}
+	for _, ranges := range c.allowEditRanges {
+		for _, aer := range ranges {
+			if !aer.Range.isValid() {
+				return fmt.Errorf("%w: %s", ErrInvalidRange, aer.Range)
+			}
+			if err := checkPassword(aer.Password); err != nil {
+				return err
+			}
+		}
+	}
+	for sheet := range c.allowEditRanges {
+		if err := checkAllowEditRanges(c.sheetAllowEditRanges(sheet)); err != nil {
+			return err
+		}
+	}
+	for _, protections := range c.cellProtections {
+		for _, cp := range protections {
+			if !cp.r.isValid() {
+				return fmt.Errorf("%w: %s", ErrInvalidRange, cp.r)
+			}
+		}
+	}
+	for _, p := range c.panes {
+		if !p.isValid() {
+			return ErrInvalidPane
+		}
+	}
+	for sheet := range c.pageSetups {
+		if err := c.sheetPageSetup(sheet).check(); err != nil {
+			return err
+		}
+	}
+	for _, r := range c.printAreas {
+		if !r.isValid() {
+			return fmt.Errorf("%w: %s", ErrInvalidRange, r)
+		}
+	}
+	for _, r := range c.printTitleRows {
+		if !r.isValid(65535) {
+			return fmt.Errorf("%w: rows %d:%d", ErrInvalidRange, r.first+1, r.last+1)
+		}
+	}
+	for _, r := range c.printTitleColumns {
+		if !r.isValid(255) {
+			return fmt.Errorf("%w: columns %s:%s", ErrInvalidRange, columnName(r.first), columnName(r.last))
+		}
+	}
+	for _, hfs := range []map[int]HeaderFooter{c.headers, c.footers} {
+		for _, hf := range hfs {
+			if err := hf.check(); err != nil {
+				return err
+			}
+		}
+	}
+
+	parser := c.newCellParser()
+	sanitizer := &sanitizer{policy: c.sanitizePolicy, parser: parser}
+
+	stringCollection, err := getStringCollectionFromCsvFile(ctx, r, c.csvFileName, c.csvDelimiter, sanitizer)
+	c.sanitizeReport = sanitizer.report
+	if err != nil {
+		return err
+	}
+
+	styles := newStyleTable()
+
+	// The header rows are repeated at the top of every worksheet
+	headerRows := c.headerRows
+	if headerRows > len(stringCollection.stringGrid) {
+		headerRows = len(stringCollection.stringGrid)
+	}
+	header := stringCollection.stringGrid[:headerRows]
+	dataRows := stringCollection.stringGrid[headerRows:]
+	rowsPerSheet := 65535 - headerRows
+
+	if (len(dataRows)+rowsPerSheet-1)/rowsPerSheet > 255 {
+		return ErrTooManyWorksheets
+	}
+
+	wsArr := make([]worksheet, 0)
+	n := 0
+	for i := 0; i < len(dataRows) || (i == 0 && headerRows > 0); i += rowsPerSheet {
+		wsName := worksheetName(n)
+
+		mergedCells := c.sheetMergedCells(n)
+		for j := range mergedCells {
+			for _, other := range mergedCells[:j] {
+				if mergedCells[j].overlaps(other) {
+					return fmt.Errorf("%w: %s and %s on %s", ErrOverlappingMergedCells, other, mergedCells[j], wsName)
+				}
+			}
+		}
+
+		var autoWidths map[int]int
+		if c.autoColumnWidths {
+			autoWidths = make(map[int]int)
+		}
+
+		last := i + rowsPerSheet
+		if last > len(dataRows) {
+			last = len(dataRows)
+		}
+		grid := dataRows[i:last]
+		if headerRows > 0 {
+			grid = append(append(make([][]string, 0, headerRows+last-i), header...), grid...)
+		}
+
+		wsArr = append(wsArr, worksheet{
+			Name:            wsName,
+			Grid:            grid,
+			ColumnWidths:    c.columnWidths,
+			HeaderRows:      headerRows,
+			pane:            c.sheetPane(n, headerRows),
+			autoFilter:      c.autoFilter,
+			mergedCells:     mergedCells,
+			quoteFormulas:   c.sanitizePolicy == SanitizeEscape || c.sanitizePolicy == SanitizeStrip,
+			hyperlinks:      c.hyperlinks,
+			linkColumns:     c.linkColumns,
+			noteColumns:     c.noteColumns,
+			explicitNotes:   append(append([]Note{}, c.notes[AllSheets]...), c.notes[n]...),
+			noteAuthor:      c.userName(),
+			charts:          append(append([]Chart{}, c.charts[AllSheets]...), c.charts[n]...),
+			protection:      c.sheetProtection(n),
+			pageSetup:       c.sheetPageSetup(n),
+			header:          sheetHeaderFooter(c.headers, n),
+			footer:          sheetHeaderFooter(c.footers, n),
+			printArea:       c.sheetPrintArea(n),
+			titleRows:       sheetLineRange(c.printTitleRows, n),
+			titleColumns:    sheetLineRange(c.printTitleColumns, n),
+			editRanges:      c.sheetAllowEditRanges(n),
+			cellProtections: append(append([]cellProtection{}, c.cellProtections[AllSheets]...), c.cellProtections[n]...),
+			autoWidths:      autoWidths,
+			maxColumnWidth:  c.maxColumnWidth,
+			parser:          parser,
+			styles:          styles,
+		})
+		n++
+	}
+
+	worksheetNames := make([]string, 0)
+	for _, ws := range wsArr {
+		worksheetNames = append(worksheetNames, ws.Name)
+	}
+
+	drawings := &drawingGroup{}
+	definedNames := make([]definedName, 0)
+	for i := range wsArr {
+		for _, img := range append(append([]Image{}, c.images[AllSheets]...), c.images[i]...) {
+			si, err := newSheetImage(img)
+			if err != nil {
+				return err
+			}
+			si.pib = drawings.addBlip(si.blipType, si.Data)
+			wsArr[i].images = append(wsArr[i].images, si)
+		}
+		wsArr[i].drawing = drawings.addDrawing(wsArr[i].shapes(i))
+		definedNames = append(definedNames, wsArr[i].definedNames(i)...)
+		if wsArr[i].validations, err = c.sheetDataValidations(i, worksheetNames); err != nil {
+			return err
+		}
+	}
+
+	worksheetDatas := make([]string, 0)
+	for _, ws := range wsArr {
+		ws.sheetNames = worksheetNames
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		wsData, err := ws.getData(&stringCollection)
+		if err != nil {
+			return err
+		}
+		worksheetDatas = append(worksheetDatas, wsData)
+	}
+
+	worksheetSizes := make([]int, 0)
+	for _, wsd := range worksheetDatas {
+		worksheetSizes = append(worksheetSizes, len(wsd))
+	}
+
+	workbook := workbook{
+		WorksheetSizes:   worksheetSizes,
+		WorksheetNames:   worksheetNames,
+		stringCollection: &stringCollection,
+		styles:           styles,
+		definedNames:     definedNames,
+		drawings:         drawings,
+		protection:       c.workbookProtection,
+		fileSharing:      c.getFileSharing(),
+	}
+	if c.password != "" {
+		if workbook.encryption, err = newRC4Encryption(c.password); err != nil {
+			return err
+		}
+	}
+
+	var data strings.Builder
+	data.WriteString(workbook.getWorksheetSizesData())
+
+	for _, wsd := range worksheetDatas {
+		data.WriteString(wsd)
+	}
+
+	workbookStream := data.String()
+	if workbook.encryption != nil {
+		stream := []byte(workbookStream)
+		if err = workbook.encryption.encryptStream(stream); err != nil {
+			return err
+		}
+		workbookStream = string(stream)
+	}
+
+	// The streams are the right siblings of each other in the order of the name lengths: workbook, SummaryInformation, DocumentSummaryInformation
+	rootPps := pps{0, ascToUcs("Root Entry"), olePpsTypeRoot, 0xFFFFFFFF, 0xFFFFFFFF, 1, "", 0, 0}
+	workbookPps := pps{1, ascToUcs("workbook"), olePpsTypeFile, 0xFFFFFFFF, 2, 0xFFFFFFFF, workbookStream, 0, 0}
+
+	summaryInformation := getSummaryInformation(c.title, c.subject, c.creator, c.keywords, c.description, c.lastModifiedBy, CreatedAtInt, ModifiedAtInt)
+	summaryInformationPps := pps{2, ascToUcs(fmt.Sprintf("%c%s", rune(5), "SummaryInformation")), olePpsTypeFile, 0xFFFFFFFF, 3, 0xFFFFFFFF, summaryInformation, 0, 0}
+
+	documentSummaryInformation := getDocumentSummaryInformation(c.company, c.manager, c.category, c.properties)
+	documentSummaryInformationPps := pps{3, ascToUcs(fmt.Sprintf("%c%s", rune(5), "DocumentSummaryInformation")), olePpsTypeFile, 0xFFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF, documentSummaryInformation, 0, 0}
+
+	aList := []pps{rootPps, workbookPps, summaryInformationPps, documentSummaryInformationPps}
+
+	iSBDcnt, iBBcnt, iPPScnt := calcSize(aList) // change types to uint32 TODO
+
+	// Content of this buffer is result xls file
+	resultBuffer := new(bytes.Buffer)
+
+	saveHeader(resultBuffer, iSBDcnt, iBBcnt, iPPScnt)
+
+	smallData := makeSmallData(resultBuffer, aList)
+	aList[0].Data = smallData
+
+	// Write BB
+	saveBigData(resultBuffer, iSBDcnt, aList)
+
+	// Write PPS
+	savePps(resultBuffer, aList)
+
+	// Write Big Block Depot and BDList and Adding Header information
+	saveBbd(resultBuffer, iSBDcnt, iBBcnt, iPPScnt)
+
+	if _, err = w.Write(resultBuffer.Bytes()); err != nil {
+		return &XlsWriteError{c.xlsFileName, err}
+	}
+
+	return nil
+}
+
+// newCellParser creates the cellParser of the converter options
+func (c *Csv2XlsConverter) newCellParser() *cellParser {
+	parser := &cellParser{
+		defaultType:        c.defaultColumnType,
+		columnTypes:        c.columnTypes,
+		decimalSeparator:   c.decimalSeparator,
+		thousandsSeparator: c.thousandsSeparator,
+		dateLayouts:        make([]dateLayout, 0, len(c.dateLayouts)),
+		dateFormat:         c.dateFormat,
+		dateTimeFormat:     c.dateTimeFormat,
+		booleanTokens:      newBooleanTokens(c.trueTokens, c.falseTokens),
+		columnBoolTokens:   make(map[int]booleanTokens, len(c.columnBoolTokens)),
+		formulas:           c.formulas,
+	}
+	for column, tokens := range c.columnBoolTokens {
+		parser.columnBoolTokens[column] = newBooleanTokens(tokens[0], tokens[1])
+	}
+	for _, layout := range c.dateLayouts {
+		parser.dateLayouts = append(parser.dateLayouts, newDateLayout(layout))
+	}
+
+	return parser
+}
+
+// SanitizationReport returns the csv values altered by the sanitization during the last conversion
+func (c *Csv2XlsConverter) SanitizationReport() []SanitizedCell {
+	return c.sanitizeReport
+}
+
+// WithTitle ...
+func (c *Csv2XlsConverter) WithTitle(title string) *Csv2XlsConverter {
+	c.title = title
+	return c
+}
+
+// WithSubject ...
+func (c *Csv2XlsConverter) WithSubject(subject string) *Csv2XlsConverter {
+	c.subject = subject
+	return c
+}
+
+// WithCreator ...
+func (c *Csv2XlsConverter) WithCreator(creator string) *Csv2XlsConverter {
+	c.creator = creator
+	return c
+}
+
+// WithKeywords ...
+func (c *Csv2XlsConverter) WithKeywords(keywords string) *Csv2XlsConverter {
+	c.keywords = keywords
+	return c
+}
+
+// WithDescription ...
+func (c *Csv2XlsConverter) WithDescription(description string) *Csv2XlsConverter {
+	c.description = description
+	return c
+}
+
+// WithLastModifiedBy ...
+func (c *Csv2XlsConverter) WithLastModifiedBy(lastModifiedBy string) *Csv2XlsConverter {
+	c.lastModifiedBy = lastModifiedBy
+	return c
+}
+
+// WithCompany sets the Company property of the xls file
+func (c *Csv2XlsConverter) WithCompany(company string) *Csv2XlsConverter {
+	c.company = company
+	return c
+}
+
+// WithManager sets the Manager property of the xls file
+func (c *Csv2XlsConverter) WithManager(manager string) *Csv2XlsConverter {
+	c.manager = manager
+	return c
+}
+
+// WithCategory sets the Category property of the xls file
+func (c *Csv2XlsConverter) WithCategory(category string) *Csv2XlsConverter {
+	c.category = category
+	return c
+}
+
+// WithCustomProperty adds a user-defined property to the xls file, the value is a string, an int, a float64, a bool or a time.Time.
+// A property with the same name replaces the previous one. Use ParseProperty to convert a "name=value" or "name:type=value" string.
+// It returns ErrInvalidProperty from the conversion if the name is empty or longer than 255 characters or the value has another type.
+func (c *Csv2XlsConverter) WithCustomProperty(name string, value interface{}) *Csv2XlsConverter {
+	for i := range c.properties {
+		if strings.EqualFold(c.properties[i].Name, name) {
+			c.properties[i].Value = value
+			return c
+		}
+	}
+	c.properties = append(c.properties, CustomProperty{name, value})
+	return c
+}
+
+// WithDefaultColumnType sets how the values of columns without WithColumnType are written, ColumnTypeAuto by default
+func (c *Csv2XlsConverter) WithDefaultColumnType(columnType ColumnType) *Csv2XlsConverter {
+	c.defaultColumnType = columnType
+	return c
+}
+
+// WithColumnType sets how the values of the column with 0-based index column are written
+func (c *Csv2XlsConverter) WithColumnType(column int, columnType ColumnType) *Csv2XlsConverter {
+	c.columnTypes[column] = columnType
+	return c
+}
+
+// WithNumberSeparators sets the separators used by numeric csv values, "." and "," by default.
+// A zero thousandsSeparator disables digit grouping.
+// It returns ErrInvalidNumberSeparators from the conversion if the decimal separator is zero or a digit or both separators are equal.
+func (c *Csv2XlsConverter) WithNumberSeparators(decimalSeparator, thousandsSeparator rune) *Csv2XlsConverter {
+	c.decimalSeparator = decimalSeparator
+	c.thousandsSeparator = thousandsSeparator
+	return c
+}
+
+// WithDateLayouts sets the Go time layouts of the csv values that are written as dates, DefaultDateLayouts by default.
+// No layouts disable the date detection.
+func (c *Csv2XlsConverter) WithDateLayouts(layouts ...string) *Csv2XlsConverter {
+	c.dateLayouts = layouts
+	return c
+}
+
+// WithDateFormats sets the Excel number formats of the dates, DefaultDateFormat and DefaultDateTimeFormat by default.
+// dateFormat is used for the layouts without time of day, dateTimeFormat for the other ones.
+func (c *Csv2XlsConverter) WithDateFormats(dateFormat, dateTimeFormat string) *Csv2XlsConverter {
+	c.dateFormat = dateFormat
+	c.dateTimeFormat = dateTimeFormat
+	return c
+}
+
+// WithBooleanTokens sets the csv values written as TRUE and FALSE, DefaultTrueTokens and DefaultFalseTokens by default.
+// The tokens are compared case-insensitively.
+func (c *Csv2XlsConverter) WithBooleanTokens(trueTokens, falseTokens []string) *Csv2XlsConverter {
+	c.trueTokens = trueTokens
+	c.falseTokens = falseTokens
+	return c
+}
+
+// WithColumnBooleanTokens sets the csv values written as TRUE and FALSE in the column with 0-based index column,
+// e.g. "yes" and "no" or "1" and "0"
+func (c *Csv2XlsConverter) WithColumnBooleanTokens(column int, trueTokens, falseTokens []string) *Csv2XlsConverter {
+	c.columnBoolTokens[column] = [2][]string{trueTokens, falseTokens}
+	return c
+}
+
+// WithFormulas enables writing the csv values starting with "=" as formulas, e.g. "=SUM(B2:B10)" or "=worksheet1!A1*2".
+// The conversion fails with FormulaError when such a value is not a valid formula.
+// It returns ErrSanitizeFormulas from the conversion if a sanitization policy is set as well.
+func (c *Csv2XlsConverter) WithFormulas(enabled bool) *Csv2XlsConverter {
+	c.formulas = enabled
+	return c
+}
+
+// WithSanitization sets how the csv values starting with "=", "+", "-", "@", tab or carriage return are treated,
+// such values run as formulas once edited in Excel. Plain numbers like "-42" are kept as they are.
+// It returns ErrSanitizeFormulas from the conversion if the formulas are enabled as well.
+func (c *Csv2XlsConverter) WithSanitization(policy SanitizePolicy) *Csv2XlsConverter {
+	c.sanitizePolicy = policy
+	return c
+}
+
+// WithHeaderRows sets the number of the csv header rows, they are written as bold text and frozen.
+// The header rows are repeated at the top of every worksheet when the data does not fit into one worksheet.
+// It returns ErrInvalidHeaderRows from the conversion if headerRows is out of 0 - 65534.
+func (c *Csv2XlsConverter) WithHeaderRows(headerRows int) *Csv2XlsConverter {
+	c.headerRows = headerRows
+	return c
+}
+
+// WithFreezePanes freezes the rows above and the columns to the left of the cell (row, column) of the worksheet with 0-based index sheet.
+// AllSheets freezes every worksheet without its own panes. By default the header rows are frozen.
+func (c *Csv2XlsConverter) WithFreezePanes(sheet, row, column int) *Csv2XlsConverter {
+	c.panes[sheet] = pane{rows: row, cols: column}
+	return c
+}
+
+// WithSplitPanes splits the window above and to the left of the cell (row, column) of the worksheet with 0-based index sheet,
+// unlike the frozen panes the split panes scroll independently. AllSheets splits every worksheet without its own panes.
+func (c *Csv2XlsConverter) WithSplitPanes(sheet, row, column int) *Csv2XlsConverter {
+	c.panes[sheet] = pane{rows: row, cols: column, split: true}
+	return c
+}
+
+// WithAutoFilter turns on the filter drop-downs in the last header row, or in the first row when there are no header rows
+func (c *Csv2XlsConverter) WithAutoFilter(autoFilter bool) *Csv2XlsConverter {
+	c.autoFilter = autoFilter
+	return c
+}
+
+// WithColumnWidth sets the width of the column with 0-based index column in characters, it overrides the fitted width.
+// It returns ErrInvalidColumnWidth from the conversion if width is out of 0 - 255.
+func (c *Csv2XlsConverter) WithColumnWidth(column, width int) *Csv2XlsConverter {
+	c.columnWidths[column] = width
+	return c
+}
+
+// WithAutoColumnWidths turns the fitting of the column widths to the longest values on or off, it is on by default.
+// The columns are 10 characters wide when the fitting is off.
+func (c *Csv2XlsConverter) WithAutoColumnWidths(autoColumnWidths bool) *Csv2XlsConverter {
+	c.autoColumnWidths = autoColumnWidths
+	return c
+}
+
+// WithMaxColumnWidth sets the maximum width of the fitted columns in characters, DefaultMaxColumnWidth by default.
+// It wins over the minimum width of 8 characters of the fitted columns.
+// It returns ErrInvalidColumnWidth from the conversion if width is out of 1 - 255.
+func (c *Csv2XlsConverter) WithMaxColumnWidth(width int) *Csv2XlsConverter {
+	c.maxColumnWidth = width
+	return c
+}
+
+// WithHyperlinks turns the URLs like "https://example.com", the mailto: addresses and the email addresses into links,
+// the ones longer than 2079 characters stay plain text
+func (c *Csv2XlsConverter) WithHyperlinks(hyperlinks bool) *Csv2XlsConverter {
+	c.hyperlinks = hyperlinks
+	return c
+}
+
+// WithHyperlinkColumn links the cells of the 0-based column to the targets in the same row of targetColumn.
+// A target is a URL, an email address or a place in the workbook like "#worksheet1!A1", the cells with an empty target
+// or a target longer than 2079 characters are not linked.
+// It returns ErrInvalidColumn from the conversion if a column is out of 0 - 255.
+func (c *Csv2XlsConverter) WithHyperlinkColumn(column, targetColumn int) *Csv2XlsConverter {
+	c.linkColumns[column] = targetColumn
+	return c
+}
+
+// WithNoteColumn adds notes to the cells of the 0-based column, the note texts are in the same row of noteColumn.
+// The cells with an empty note text get no note, the texts longer than 32767 characters are cut.
+// It returns ErrInvalidColumn from the conversion if a column is out of 0 - 255.
+func (c *Csv2XlsConverter) WithNoteColumn(column, noteColumn int) *Csv2XlsConverter {
+	c.noteColumns[column] = noteColumn
+	return c
+}
+
+// WithNote adds a note to a cell of the worksheet with 0-based index sheet, AllSheets adds it to every worksheet.
+// The note wins over the note of the same cell from a note column. Use ReadNotes to read the notes from a JSON file.
+// It returns ErrInvalidNote from the conversion if the cell is out of the worksheet or the text is empty or longer than 32767 characters.
+func (c *Csv2XlsConverter) WithNote(sheet int, note Note) *Csv2XlsConverter {
+	c.notes[sheet] = append(c.notes[sheet], note)
+	return c
+}
+
+// WithImage places a PNG or JPEG picture on the worksheet with 0-based index sheet, AllSheets places it on every worksheet.
+// It returns ErrInvalidImage from the conversion if the picture is neither PNG nor JPEG or the cell is out of the worksheet.
+func (c *Csv2XlsConverter) WithImage(sheet int, img Image) *Csv2XlsConverter {
+	c.images[sheet] = append(c.images[sheet], img)
+	return c
+}
+
+// WithChart embeds a chart of the data rows into the worksheet with 0-based index sheet, AllSheets embeds it into every worksheet.
+// Every worksheet charts its own rows, the series names are taken from the last header row.
+// It returns ErrInvalidChart from the conversion if the type is unknown, there are no series or the range or a column is out of the worksheet.
+func (c *Csv2XlsConverter) WithChart(sheet int, chart Chart) *Csv2XlsConverter {
+	c.charts[sheet] = append(c.charts[sheet], chart)
+	return c
+}
+
+// WithMergedCells merges the cells of the range on the worksheet with 0-based index sheet, AllSheets merges them on every worksheet.
+// It returns ErrInvalidRange or ErrOverlappingMergedCells from the conversion if the range does not fit into a worksheet or overlaps another one.
+func (c *Csv2XlsConverter) WithMergedCells(sheet int, r CellRange) *Csv2XlsConverter {
+	c.mergedCells[sheet] = append(c.mergedCells[sheet], r)
+	return c
+}
+
+// sheetMergedCells returns the merged ranges of the worksheet with index idx
+func (c *Csv2XlsConverter) sheetMergedCells(idx int) []CellRange {
+	ranges := make([]CellRange, 0, len(c.mergedCells[AllSheets])+len(c.mergedCells[idx]))
+	ranges = append(ranges, c.mergedCells[AllSheets]...)
+	return append(ranges, c.mergedCells[idx]...)
+}
+
+// WithDataValidation adds the data validation rule to the worksheet with 0-based index sheet, AllSheets adds it to every worksheet.
+// It returns ErrInvalidDataValidation from the conversion if the rule is malformed.
+func (c *Csv2XlsConverter) WithDataValidation(sheet int, dv DataValidation) *Csv2XlsConverter {
+	c.dataValidations[sheet] = append(c.dataValidations[sheet], dv)
+	return c
+}
+
+// sheetDataValidations returns the compiled data validation rules of the worksheet with index idx
+func (c *Csv2XlsConverter) sheetDataValidations(idx int, sheetNames []string) ([]dataValidation, error) {
+	rules := append(append([]DataValidation{}, c.dataValidations[AllSheets]...), c.dataValidations[idx]...)
+
+	validations := make([]dataValidation, 0, len(rules))
+	for _, rule := range rules {
+		dv, err := compileDataValidation(rule, sheetNames)
+		if err != nil {
+			return nil, err
+		}
+		validations = append(validations, dv)
+	}
+
+	return validations, nil
+}
+
+// WithSheetProtection protects the worksheet with 0-based index sheet, AllSheets protects every worksheet without its own protection.
+// The password is optional, the permissions are the actions that stay allowed, e.g. DefaultSheetPermissions | AllowSort.
+// It returns ErrInvalidPassword from the conversion if the password is longer than 15 characters or is not in the Windows-1252 code page.
+func (c *Csv2XlsConverter) WithSheetProtection(sheet int, password string, permissions SheetPermission) *Csv2XlsConverter {
+	c.sheetProtections[sheet] = sheetProtection{password, permissions}
+	return c
+}
+
+// sheetProtection returns the protection of the worksheet with index idx, nil if it is not protected
+func (c *Csv2XlsConverter) sheetProtection(idx int) *sheetProtection {
+	if p, ok := c.sheetProtections[idx]; ok {
+		return &p
+	}
+	if p, ok := c.sheetProtections[AllSheets]; ok {
+		return &p
+	}
+	return nil
+}
+
+// WithAllowEditRange leaves the range of the protected worksheet with 0-based index sheet editable, AllSheets adds the range to every worksheet.
+// It returns ErrInvalidRange or ErrInvalidPassword from the conversion if the range does not fit into a worksheet, the title is longer
+// than 255 characters or is taken on the worksheet, or the password is longer than 15 characters or is not in the Windows-1252 code page.
+func (c *Csv2XlsConverter) WithAllowEditRange(sheet int, aer AllowEditRange) *Csv2XlsConverter {
+	c.allowEditRanges[sheet] = append(c.allowEditRanges[sheet], aer)
+	return c
+}
+
+// sheetAllowEditRanges returns the allow-edit ranges of every worksheet followed by the ones of the worksheet with 0-based index sheet
+func (c *Csv2XlsConverter) sheetAllowEditRanges(sheet int) []AllowEditRange {
+	ranges := append([]AllowEditRange{}, c.allowEditRanges[AllSheets]...)
+	if sheet != AllSheets {
+		ranges = append(ranges, c.allowEditRanges[sheet]...)
+	}
+	return ranges
+}
+
+// WithUnlockedCells unlocks the csv cells of the range on the worksheet with 0-based index sheet, they can be edited when the worksheet is protected.
+// AllSheets unlocks the cells on every worksheet.
+func (c *Csv2XlsConverter) WithUnlockedCells(sheet int, r CellRange) *Csv2XlsConverter {
+	c.cellProtections[sheet] = append(c.cellProtections[sheet], cellProtection{r: r, unlocked: true})
+	return c
+}
+
+// WithHiddenFormulas hides the formulas of the csv cells of the range on the worksheet with 0-based index sheet when the worksheet is protected.
+// AllSheets hides the formulas on every worksheet.
+func (c *Csv2XlsConverter) WithHiddenFormulas(sheet int, r CellRange) *Csv2XlsConverter {
+	c.cellProtections[sheet] = append(c.cellProtections[sheet], cellProtection{r: r, hidden: true})
+	return c
+}
+
+// WithWorkbookProtection protects the workbook structure, the worksheets cannot be added, removed or renamed.
+// The password is optional, windows also protects the workbook windows from moving, resizing and closing.
+// It returns ErrInvalidPassword from the conversion if the password is longer than 15 characters or is not in the Windows-1252 code page.
+func (c *Csv2XlsConverter) WithWorkbookProtection(password string, windows bool) *Csv2XlsConverter {
+	c.workbookProtection = &workbookProtection{password, windows}
+	return c
+}
+
+// WithReadOnlyRecommended makes Excel suggest opening the workbook read-only
+func (c *Csv2XlsConverter) WithReadOnlyRecommended(readOnlyRecommended bool) *Csv2XlsConverter {
+	if c.fileSharing == nil {
+		c.fileSharing = &fileSharing{}
+	}
+	c.fileSharing.readOnlyRecommended = readOnlyRecommended
+	return c
+}
+
+// WithModifyPassword sets the password to modify, without it Excel opens the workbook read-only.
+// It returns ErrInvalidPassword from the conversion if the password is longer than 15 characters or is not in the Windows-1252 code page.
+func (c *Csv2XlsConverter) WithModifyPassword(password string) *Csv2XlsConverter {
+	if c.fileSharing == nil {
+		c.fileSharing = &fileSharing{}
+	}
+	c.fileSharing.modifyPassword = password
+	return c
+}
+
+// getFileSharing returns the write reservation of the workbook, nil if the workbook is not reserved
+func (c *Csv2XlsConverter) getFileSharing() *fileSharing {
+	if c.fileSharing == nil || (!c.fileSharing.readOnlyRecommended && c.fileSharing.modifyPassword == "") {
+		return nil
+	}
+
+	// Excel shows the name of the user who reserved the workbook
+	fs := *c.fileSharing
+	fs.userName = c.userName()
+
+	return &fs
+}
+
+// userName returns the name of the user shown as the author of the notes and of the write reservation,
+// cut to the 54 characters Excel accepts
+func (c *Csv2XlsConverter) userName() string {
+	if c.creator != "" {
+		return truncateUTF16(c.creator, maxUserNameLength)
+	}
+	if c.lastModifiedBy != "" {
+		return truncateUTF16(c.lastModifiedBy, maxUserNameLength)
+	}
+	return "csv2xls"
+}
+
+// WithPassword encrypts the workbook with RC4, Excel asks for the password to open it.
+// It returns ErrInvalidPassword from the conversion if the password is longer than 255 characters.
+func (c *Csv2XlsConverter) WithPassword(password string) *Csv2XlsConverter {
+	c.password = password
+	return c
+}
+
+// WithPageSetup sets the print layout of the worksheet with 0-based index sheet, AllSheets sets it for every worksheet.
+// Start from DefaultPageSetup to change only some settings, or use WithPageSetupOptions.
+// It returns ErrInvalidPageSetup from the conversion if a setting is out of the range Excel accepts.
+func (c *Csv2XlsConverter) WithPageSetup(sheet int, ps PageSetup) *Csv2XlsConverter {
+	return c.WithPageSetupOptions(sheet, func(setup *PageSetup) { *setup = ps })
+}
+
+// WithPageSetupOptions changes some settings of the print layout of the worksheet with 0-based index sheet, AllSheets changes them for every worksheet.
+// The options of a worksheet are applied after the options of all the worksheets. Use ParsePageSetup to convert a string into the options.
+// It returns ErrInvalidPageSetup from the conversion if a setting is out of the range Excel accepts.
+func (c *Csv2XlsConverter) WithPageSetupOptions(sheet int, options ...PageSetupOption) *Csv2XlsConverter {
+	c.pageSetups[sheet] = append(c.pageSetups[sheet], options...)
+	return c
+}
+
+// sheetPageSetup returns the page setup of the worksheet with index idx
+func (c *Csv2XlsConverter) sheetPageSetup(idx int) PageSetup {
+	ps := DefaultPageSetup()
+	options := c.pageSetups[AllSheets]
+	if idx != AllSheets {
+		options = append(append([]PageSetupOption{}, options...), c.pageSetups[idx]...)
+	}
+	for _, option := range options {
+		option(&ps)
+	}
+	return ps
+}
+
+// WithHeader sets the page header of the worksheet with 0-based index sheet, AllSheets sets it for every worksheet without its own header.
+// Use ParseHeaderFooter to convert an Excel template like "&LReport&CPage &P of &N" into a HeaderFooter.
+// It returns ErrInvalidHeaderFooter from the conversion if the header has an unknown &-code or is longer than 255 characters.
+func (c *Csv2XlsConverter) WithHeader(sheet int, header HeaderFooter) *Csv2XlsConverter {
+	c.headers[sheet] = header
+	return c
+}
+
+// WithFooter sets the page footer of the worksheet with 0-based index sheet, AllSheets sets it for every worksheet without its own footer.
+// It returns ErrInvalidHeaderFooter from the conversion if the footer has an unknown &-code or is longer than 255 characters.
+func (c *Csv2XlsConverter) WithFooter(sheet int, footer HeaderFooter) *Csv2XlsConverter {
+	c.footers[sheet] = footer
+	return c
+}
+
+// sheetHeaderFooter returns the header or footer of the worksheet with index idx
+func sheetHeaderFooter(hfs map[int]HeaderFooter, idx int) HeaderFooter {
+	if hf, ok := hfs[idx]; ok {
+		return hf
+	}
+	return hfs[AllSheets]
+}
+
+// WithPrintArea prints only the range of the worksheet with 0-based index sheet, AllSheets sets it for every worksheet without its own print area.
+// It returns ErrInvalidRange from the conversion if the range does not fit into a worksheet.
+func (c *Csv2XlsConverter) WithPrintArea(sheet int, r CellRange) *Csv2XlsConverter {
+	c.printAreas[sheet] = r
+	return c
+}
+
+// sheetPrintArea returns the print area of the worksheet with index idx, nil if the whole worksheet is printed
+func (c *Csv2XlsConverter) sheetPrintArea(idx int) *CellRange {
+	if r, ok := c.printAreas[idx]; ok {
+		return &r
+	}
+	if r, ok := c.printAreas[AllSheets]; ok {
+		return &r
+	}
+	return nil
+}
+
+// WithPrintTitleRows repeats the 0-based rows from firstRow to lastRow at the top of every printed page of the worksheet with 0-based index sheet,
+// AllSheets sets them for every worksheet without its own title rows. The header rows are repeated by default.
+// It returns ErrInvalidRange from the conversion if the rows do not fit into a worksheet.
+func (c *Csv2XlsConverter) WithPrintTitleRows(sheet, firstRow, lastRow int) *Csv2XlsConverter {
+	c.printTitleRows[sheet] = lineRange{firstRow, lastRow}
+	return c
+}
+
+// WithPrintTitleColumns repeats the 0-based columns from firstColumn to lastColumn at the left of every printed page of the worksheet with 0-based index sheet,
+// AllSheets sets them for every worksheet without its own title columns.
+// It returns ErrInvalidRange from the conversion if the columns do not fit into a worksheet.
+func (c *Csv2XlsConverter) WithPrintTitleColumns(sheet, firstColumn, lastColumn int) *Csv2XlsConverter {
+	c.printTitleColumns[sheet] = lineRange{firstColumn, lastColumn}
+	return c
+}
+
+// sheetLineRange returns the print title rows or columns of the worksheet with index idx, nil if it has none
+func sheetLineRange(ranges map[int]lineRange, idx int) *lineRange {
+	if r, ok := ranges[idx]; ok {
+		return &r
+	}
+	if r, ok := ranges[AllSheets]; ok {
+		return &r
+	}
+	return nil
+}
+
+// sheetPane returns the panes of the worksheet with index idx
+func (c *Csv2XlsConverter) sheetPane(idx, headerRows int) pane {
+	if p, ok := c.panes[idx]; ok {
+		return p
+	}
+	if p, ok := c.panes[AllSheets]; ok {
+		return p
+	}
+	return pane{rows: headerRows}
+}
+
+func saveBbd(buffer *bytes.Buffer, iSbdSize, iBsize, iPpsCnt uint32) {
+	// Calculate Basic Setting
+	var iBbCnt uint32 = 512 / oleLongIntSize
+	var i1stBdL uint32 = (512 - 0x4C) / oleLongIntSize
+
+	var iBdExL uint32 = 0
+	iAll := iBsize + iPpsCnt + iSbdSize
+	iAllW := iAll
+	iBdCntW := uint32(math.Floor(float64(iAllW) / float64(iBbCnt)))
+	if iAllW%iBbCnt > 0 {
+		iBdCntW++
+	}
+	iBdCnt := uint32(math.Floor(float64(iAll+iBdCntW) / float64(iBbCnt)))
+	if (iAllW+iBdCntW)%iBbCnt > 0 {
+		iBdCnt++
+	}
+	// Calculate BD count
+	if iBdCnt > i1stBdL {
+		for {
+			iBdExL++
+			iAllW++
+			iBdCntW = uint32(math.Floor(float64(iAllW) / float64(iBbCnt)))
+			if iAllW%iBbCnt > 0 {
+				iBdCntW++
+			}
+			iBdCnt = uint32(math.Floor(float64(iAllW+iBdCntW) / float64(iBbCnt)))
+			if (iAllW+iBdCntW)%iBbCnt > 0 {
+				iBdCnt++
+			}
+			if iBdCnt <= (iBdExL*iBbCnt + i1stBdL) {
+				break
+			}
+		}
+	}
+
+	// Making BD
+	// Set for SBD
+	if iSbdSize > 0 {
+		var i uint32
+		for i = 0; i < (iSbdSize - 1); i++ {
+			putVar(buffer, i+1)
+		}
+		putVar(buffer, []byte("\xFE\xFF\xFF\xFF")) // uint32(-2)
+	}
+
+	// Set for B
+	var i uint32
+	for i = 0; i < (iBsize - 1); i++ {
+		putVar(buffer, i+iSbdSize+1)
+	}
+	putVar(buffer, []byte("\xFE\xFF\xFF\xFF"))
+
+	// Set for PPS
+	for i = 0; i < (iPpsCnt - 1); i++ {
+		putVar(buffer, i+iSbdSize+iBsize+1)
+	}
+	putVar(buffer, []byte("\xFE\xFF\xFF\xFF"))
+
+	// Set for BBD itself ( 0xFFFFFFFD : BBD)
+	for i = 0; i < iBdCnt; i++ {
+		putVar(buffer, uint32(0xFFFFFFFD))
+	}
+
+	// Set for ExtraBDList
+	for i = 0; i < iBdExL; i++ {
+		putVar(buffer, uint32(0xFFFFFFFC))
+	}
+
+	// Adjust for Block
+	if (iAllW+iBdCnt)%iBbCnt > 0 {
+		iBlock := iBbCnt - ((iAllW + iBdCnt) % iBbCnt)
+		for i = 0; i < iBlock; i++ {
+			putVar(buffer, []byte("\xFF\xFF\xFF\xFF"))
+		}
+	}
+
+	// Extra BDList
+	if iBdCnt > i1stBdL {
+		var iN, iNb uint32
+		for i = i1stBdL; i < iBdCnt; i++ {
+			if iN >= (iBbCnt - 1) {
+				iN = 0
+				iNb++
+				putVar(buffer, iAll+iBdCnt+iNb)
+			}
+			putVar(buffer, iBsize+iSbdSize+iPpsCnt+i)
+			iN++
+		}
+		if (iBdCnt-i1stBdL)%(iBbCnt-1) > 0 {
+			iB := (iBbCnt - 1) - ((iBdCnt - i1stBdL) % (iBbCnt - 1))
+			for i = 0; i < iB; i++ {
+				putVar(buffer, []byte("\xFF\xFF\xFF\xFF"))
+			}
+		}
+		putVar(buffer, []byte("\xFE\xFF\xFF\xFF"))
+	}
+}
+
+func savePps(buffer *bytes.Buffer, raList []pps) {
+	// Save each PPS WK
+	for _, pps := range raList {
+		putVar(buffer, []byte(pps.getPpsWk())) // maybe it'll be better to change return type to []byte
+	}
+	// Adjust for Block
+	iCnt := len(raList)
+	iBCnt := 512 / olePpsSize
+	if iCnt%iBCnt > 0 {
+		putVar(buffer, []byte(strings.Repeat("\x00", (iBCnt-(iCnt%iBCnt))*olePpsSize)))
+	}
+}
+
+func saveBigData(buffer *bytes.Buffer, iStBlk uint32, raList []pps) {
+	// cycle through PPS's
+	for i, _ := range raList {
+		if raList[i].PpsType != olePpsTypeDir {
+			raList[i].Size = uint32(len(raList[i].Data))
+			if raList[i].Size >= oleDataSizeSmall || (raList[i].PpsType == olePpsTypeRoot && len(raList[i].Data) != 0) {
+				putVar(buffer, []byte(raList[i].Data))
+
+				if raList[i].Size%512 > 0 {
+					putVar(buffer, []byte(strings.Repeat("\x00", 512-int(raList[i].Size)%512)))
+				}
+				// Set For PPS
+				raList[i].StartBlock = iStBlk
+				iStBlk += uint32(math.Floor(float64(raList[i].Size) / 512))
+				if raList[i].Size%512 > 0 {
+					iStBlk++
+				}
+			}
+		}
+	}
+}
+
+func makeSmallData(buffer *bytes.Buffer, raList []pps) string {
+	var smallData strings.Builder
+	var iSmBlk uint32 = 0
+
+	for i, _ := range raList {
+		// Make SBD, small data string
+		if raList[i].PpsType == olePpsTypeFile {
+			if raList[i].Size <= 0 {
+				continue
+			}
+
+			if raList[i].Size < oleDataSizeSmall {
+				iSmbCnt := uint32(math.Floor(float64(raList[i].Size) / 64))
+				if raList[i].Size%64 > 0 {
+					iSmbCnt++
+				}
+				jB := iSmbCnt - 1
+				var j uint32
+				for j = 0; j < jB; j++ {
+					putVar(buffer, j+iSmBlk+1)
+				}
+				putVar(buffer, []byte("\xFE\xFF\xFF\xFF")) // uint32(-2)
+
+				smallData.WriteString(raList[i].Data)
+				if raList[i].Size%64 > 0 {
+					smallData.WriteString(strings.Repeat("\x00", 64-int(raList[i].Size%64)))
+				}
+				// Set for PPS
+				raList[i].StartBlock = iSmBlk
+				iSmBlk += iSmbCnt
+			}
+		}
+	}
+
+	iSbCnt := uint32(math.Floor(512.0 / oleLongIntSize))
+	if iSmBlk%iSbCnt > 0 {
+		iB := iSbCnt - (iSmBlk % iSbCnt)
+		var i uint32
+		for i = 0; i < iB; i++ {
+			putVar(buffer, []byte("\xFF\xFF\xFF\xFF"))
+		}
+	}
+
+	return smallData.String()
+}
+
+func saveHeader(buffer *bytes.Buffer, iSBDcnt, iBBcnt, iPPScnt uint32) {
+	// Calculate Basic Setting
+	var iBlCnt uint32 = 512 / oleLongIntSize
+	var i1stBdL uint32 = (512 - 0x4C) / oleLongIntSize
+
+	var iBdExL uint32 = 0
+	iAll := uint32(iBBcnt + iPPScnt + iSBDcnt)
+	iAllW := iAll
+	iBdCntW := uint32(math.Floor(float64(iAllW) / float64(iBlCnt)))
+	if iAllW%iBlCnt > 0 {
+		iBdCntW++
+	}
+	iBdCnt := uint32(math.Floor(float64(iAll+iBdCntW) / float64(iBlCnt)))
+	if (iAllW+iBdCntW)%iBlCnt > 0 {
+		iBdCnt++
+	}
+
+	// Calculate BD count
+	if iBdCnt > i1stBdL {
+		for {
+			iBdExL++
+			iAllW++
+			iBdCntW = uint32(math.Floor(float64(iAllW) / float64(iBlCnt)))
+			if iAllW%iBlCnt > 0 {
+				iBdCntW++
+			}
+			iBdCnt = uint32(math.Floor(float64(iAllW+iBdCntW) / float64(iBlCnt)))
+			if (iAllW+iBdCntW)%iBlCnt > 0 {
+				iBdCnt++
+			}
+			if iBdCnt <= (iBdExL*iBlCnt + i1stBdL) {
+				break
+			}
+		}
+	}
+
+	// Save Header
+	putVar(buffer,
+		[]byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1"),
+		[]byte("\x00\x00\x00\x00"),
+		[]byte("\x00\x00\x00\x00"),
+		[]byte("\x00\x00\x00\x00"),
+		[]byte("\x00\x00\x00\x00"),
+		uint16(0x3b),
+		uint16(0x03),
+		[]byte("\xFE\xFF"), // uint16(-2),
+		uint16(9),
+		uint16(6),
+		uint16(0),
+		[]byte("\x00\x00\x00\x00"),
+		[]byte("\x00\x00\x00\x00"),
+		iBdCnt,
+		iBBcnt+iSBDcnt,
+		uint32(0),
+		uint32(0x1000),
+	)
+	if iSBDcnt > 0 {
+		putVar(buffer, uint32(0))
+	} else {
+		putVar(buffer, []byte("\xFE\xFF\xFF\xFF"))
+	}
+	putVar(buffer, iSBDcnt)
+
+	// Extra BDList Start, Count
+	if iBdCnt < i1stBdL {
+		putVar(buffer,
+			[]byte("\xFE\xFF\xFF\xFF"), // Extra BDList Start
+			uint32(0),                  // Extra BDList Count
+		)
+	} else {
+		putVar(buffer, iAll+iBdCnt, iBdExL)
+	}
+
+	// BDList
+	var i uint32
+	for i = 0; i < i1stBdL && i < iBdCnt; i++ {
+		putVar(buffer, iAll+i)
+	}
+	if i < i1stBdL {
+		jB := i1stBdL - i
+		var j uint32
+		for j = 0; j < jB; j++ {
+			putVar(buffer, []byte("\xFF\xFF\xFF\xFF"))
+		}
+	}
+}
+
+func calcSize(aList []pps) (uint32, uint32, uint32) {
+	var iSBDcnt, iBBcnt, iPPScnt uint32 = 0, 0, 0
+
+	iSBcnt := 0
+	iCount := len(aList)
+	for i := 0; i < iCount; i++ {
+		if aList[i].PpsType == olePpsTypeFile {
+			aList[i].Size = uint32(len(aList[i].Data))
+
+			if aList[i].Size < oleDataSizeSmall {
+				iSBcnt += int(math.Floor(float64(aList[i].Size) / 64))
+				if aList[i].Size%64 > 0 {
+					iSBcnt++
+				}
+			} else {
+				iBBcnt += uint32(math.Floor(float64(aList[i].Size) / 512))
+				if aList[i].Size%512 > 0 {
+					iBBcnt++
+				}
+			}
+		}
+	}
+
+	iSlCnt := int(math.Floor(512 / oleLongIntSize))
+	if (math.Floor(float64(iSBcnt)/float64(iSlCnt)) + float64(iSBcnt%iSlCnt)) > 0 {
+		iSBDcnt = 1
+	}
+
+	iSmallLen := float64(iSBcnt) * 64
+	iBBcnt += uint32(math.Floor(iSmallLen / 512))
+	if int(iSmallLen)%512 > 0 {
+		iBBcnt++
+	}
+	iCnt := len(aList)
+	iBdCnt := float64(512) / olePpsSize
+	iPPScnt = uint32(math.Floor(float64(iCnt) / iBdCnt))
+	if iCnt%int(iBdCnt) > 0 {
+		iPPScnt++
+	}
+
+	return iSBDcnt, iBBcnt, iPPScnt
+}
+
+func getSummaryInformation(title, subject, creator, keywords, description, lastModifiedBy string, created, modified int64) string {
+	buffer := new(bytes.Buffer)
+
+	// offset: 0; size: 2; must be 0xFE 0xFF (UTF-16 LE byte order mark)
+	putVar(buffer, uint16(0xFFFE))
+	// offset: 2; size: 2;
+	putVar(buffer, uint16(0x0000))
+	// offset: 4; size: 2; OS version
+	putVar(buffer, uint16(0x0106))
+	// offset: 6; size: 2; OS indicator
+	putVar(buffer, uint16(0x0002))
+	// offset: 8; size: 16
+	putVar(buffer, uint32(0x00), uint32(0x00), uint32(0x00), uint32(0x00))
+	// offset: 24; size: 4; section count
+	putVar(buffer, uint32(0x0001))
+
+	// offset: 28; size: 16; first section's class id: 02 d5 cd d5 9c 2e 1b 10 93 97 08 00 2b 2c f9 ae
+	putVar(buffer, uint16(0x85E0), uint16(0xF29F), uint16(0x4FF9), uint16(0x1068), uint16(0x91AB), uint16(0x0008), uint16(0x272B), uint16(0xD9B3))
+	// offset: 44; size: 4; offset of the start
+	putVar(buffer, uint32(0x30))
+
+	dataSections := make([]dataSectionItem, 0)
+
+	// CodePage : CP-1252
+	dataSections = append(dataSections, dataSectionItem{0x01, 0, 0x02, 1252, "", 0})
+
+	// Title
+	if title != "" {
+		dataSections = append(dataSections, dataSectionItem{0x02, 0, 0x1E, 0, title, uint32(len(title))})
+	}
+
+	// Subject
+	if subject != "" {
+		dataSections = append(dataSections, dataSectionItem{0x03, 0, 0x1E, 0, subject, uint32(len(subject))})
+	}
+
+	// Author (Creator)
+	if creator != "" {
+		dataSections = append(dataSections, dataSectionItem{0x04, 0, 0x1E, 0, creator, uint32(len(creator))})
+	}
+
+	// Keywords
+	if keywords != "" {
+		dataSections = append(dataSections, dataSectionItem{0x05, 0, 0x1E, 0, keywords, uint32(len(keywords))})
+	}
+
+	// Comments (Description)
+	if description != "" {
+		dataSections = append(dataSections, dataSectionItem{0x06, 0, 0x1E, 0, description, uint32(len(description))})
+	}
+
+	// Last Saved By (LastModifiedBy)
+	if lastModifiedBy != "" {
+		dataSections = append(dataSections, dataSectionItem{0x08, 0, 0x1E, 0, lastModifiedBy, uint32(len(lastModifiedBy))})
+	}
+
+	// Created Date/Time
+	if created != 0 {
+		dataSections = append(dataSections, dataSectionItem{0x0C, 0, 0x40, 0, localDateToOLE(created), 0})
+	}
+
+	// Modified Date/Time
+	if modified != 0 {
+		dataSections = append(dataSections, dataSectionItem{0x0D, 0, 0x40, 0, localDateToOLE(modified), 0})
+	}
+
+	// Security
+	dataSections = append(dataSections, dataSectionItem{0x13, 0, 0x03, 0x00, "", 0})
+
+	writePropertySection(buffer, codePageWindows1252, dataSections)
+
+	return buffer.String()
+}
+
+// writePropertySection writes a section of a property set with its header, the property offsets and the values,
+// the strings are written in the code page of the section
+func writePropertySection(buffer *bytes.Buffer, codePage uint16, dataSections []dataSectionItem) {
+	dataSectionSummary := new(bytes.Buffer)
+	dataSectionContent := new(bytes.Buffer)
+	dataSectionNumProps := uint32(len(dataSections))
+	dataSectionContentOffset := 8 + dataSectionNumProps*8
+
+	for _, dataSection := range dataSections {
+		// Summary
+		putVar(dataSectionSummary, dataSection.summary)
+		// Offset
+		putVar(dataSectionSummary, dataSectionContentOffset)
+		// The dictionary of the custom property names has no type
+		if dataSection.summary == 0x00 {
+			putVar(dataSectionContent, []byte(dataSection.dataString))
+			dataSectionContentOffset += dataSection.dataLength
+			continue
+		}
+		// DataType
+		putVar(dataSectionContent, dataSection.sType)
+		// Data
+		if dataSection.sType == 0x02 { // 2 byte signed integer
+			putVar(dataSectionContent, dataSection.dataInt)
+			dataSectionContentOffset += 8
+		} else if dataSection.sType == 0x03 || dataSection.sType == 0x0B { // 4 byte signed integer or 2 byte boolean
+			putVar(dataSectionContent, dataSection.dataInt)
+			dataSectionContentOffset += 8
+		} else if dataSection.sType == 0x1E { // null-terminated string prepended by dword string length
+			// Null-terminated string
+			data := propertyString(dataSection.dataString, codePage)
+
+			// Complete the string with null string for being a %4
+			if pad := len(data) % 4; pad != 0 {
+				data = append(data, make([]byte, 4-pad)...)
+			}
+
+			putVar(dataSectionContent, uint32(len(data)))
+			putVar(dataSectionContent, data)
+
+			dataSectionContentOffset += 8 + uint32(len(data))
+		} else if dataSection.sType == 0x05 { // 8 byte floating point number
+			putVar(dataSectionContent, []byte(dataSection.dataString))
+			dataSectionContentOffset += 4 + 8
+		} else if dataSection.sType == 0x40 { // Filetime (64-bit value representing the number of 100-nanosecond intervals since January 1, 1601)
+			putVar(dataSectionContent, []byte(dataSection.dataString))
+			dataSectionContentOffset += 4 + 8
+		}
+		// Data Type Not Used at the moment
+	}
+	// Now dataSectionContentOffset contains the size of the content
+
+	// section header
+	// offset: $secOffset; size: 4; section length
+	//         + x  Size of the content (summary + content)
+	putVar(buffer, dataSectionContentOffset)
+
+	// offset: $secOffset+4; size: 4; property count
+	putVar(buffer, dataSectionNumProps)
+
+	// Section Summary
+	putVar(buffer, dataSectionSummary.Bytes())
+
+	// Section Content
+	putVar(buffer, dataSectionContent.Bytes())
+}
+
+func getDocumentSummaryInformation(company, manager, category string, properties []CustomProperty) string {
+	buffer := new(bytes.Buffer)
+
+	// offset: 0; size: 2; must be 0xFE 0xFF (UTF-16 LE byte order mark)
+	putVar(buffer, uint16(0xFFFE))
+	// offset: 2; size: 2;
+	putVar(buffer, uint16(0x0000))
+	// offset: 4; size: 2; OS version
+	putVar(buffer, uint16(0x0106))
+	// offset: 6; size: 2; OS indicator
+	putVar(buffer, uint16(0x0002))
+	// offset: 8; size: 16
+	putVar(buffer, uint32(0x00), uint32(0x00), uint32(0x00), uint32(0x00))
+
+	// The first section has the document properties, the second one the custom properties
+	var sectionCount uint32 = 1
+	if len(properties) > 0 {
+		sectionCount = 2
+	}
+	// offset: 24; size: 4; section count
+	putVar(buffer, sectionCount)
+
+	dataSections := make([]dataSectionItem, 0)
+
+	// CodePage : UTF-16LE
+	dataSections = append(dataSections, dataSectionItem{0x01, 0, vtI2, codePageUnicode, "", 0})
+
+	// Category
+	if category != "" {
+		dataSections = append(dataSections, dataSectionItem{pidCategory, 0, vtLPStr, 0, category, uint32(len(category))})
+	}
+
+	// Manager
+	if manager != "" {
+		dataSections = append(dataSections, dataSectionItem{pidManager, 0, vtLPStr, 0, manager, uint32(len(manager))})
+	}
+
+	// Company
+	if company != "" {
+		dataSections = append(dataSections, dataSectionItem{pidCompany, 0, vtLPStr, 0, company, uint32(len(company))})
+	}
+
+	documentSection := new(bytes.Buffer)
+	writePropertySection(documentSection, codePageUnicode, dataSections)
+
+	// offset: 28; size: 16; first section's class id: 02 d5 cd d5 9c 2e 1b 10 93 97 08 00 2b 2c f9 ae
+	putVar(buffer, uint16(0xD502), uint16(0xD5CD), uint16(0x2E9C), uint16(0x101B), uint16(0x9793), uint16(0x0008), uint16(0x2C2B), uint16(0xAEF9))
+	// offset: 44; size: 4; offset of the start
+	putVar(buffer, 28+20*sectionCount)
+
+	if len(properties) == 0 {
+		putVar(buffer, documentSection.Bytes())
+		return buffer.String()
+	}
+
+	// The custom properties: the dictionary of the names, the code page and the values
+	customSections := []dataSectionItem{propertyDictionary(properties), {0x01, 0, vtI2, codePageUnicode, "", 0}}
+	for i, p := range properties {
+		customSections = append(customSections, p.dataSection(uint32(i+2)))
+	}
+
+	// offset: 48; size: 16; second section's class id: 05 d5 cd d5 9c 2e 1b 10 93 97 08 00 2b 2c f9 ae
+	putVar(buffer, uint16(0xD505), uint16(0xD5CD), uint16(0x2E9C), uint16(0x101B), uint16(0x9793), uint16(0x0008), uint16(0x2C2B), uint16(0xAEF9))
+	// offset: 64; size: 4; offset of the second section
+	putVar(buffer, 28+20*sectionCount+uint32(documentSection.Len()))
+
+	putVar(buffer, documentSection.Bytes())
+	writePropertySection(buffer, codePageUnicode, customSections)
+
+	return buffer.String()
+}
+
+// getStringCollectionFromCsvFile reads csv data from in, csvFileName is used for error reporting only
+func getStringCollectionFromCsvFile(ctx context.Context, in io.Reader, csvFileName string, delimiter rune, sanitizer *sanitizer) (stringCollection, error) {
+	sc := stringCollection{make([][]string, 0), make(map[string]int, 0), make([]string, 0), 0, 0, sanitizer}
+
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.Comma = delimiter
+	r.LazyQuotes = true
+
+	for i := 0; ; i++ {
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return sc, err
+			}
+		}
+
+		record, err := r.Read()
+		// Stop at EOF.
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return sc, &CsvReadError{csvFileName, err}
+		}
+
+		if err = sc.addRow(record); err != nil {
+			return sc, err
+		}
+	}
+
+	return sc, nil
+}
+
+// stringCollection ...
+type stringCollection struct {
+	stringGrid   [][]string
+	stringMap    map[string]int
+	stringList   []string
+	stringTotal  int
+	stringUnique int
+	sanitizer    *sanitizer
+}
+
+func (sc *stringCollection) addRow(row []string) error {
+	rowIdx := len(sc.stringGrid)
+	for columnIdx, str := range row {
+		sanitized, err := sc.sanitizer.sanitize(rowIdx, columnIdx, str)
+		if err != nil {
+			return err
+		}
+		row[columnIdx] = sanitized
+	}
+	sc.stringGrid = append(sc.stringGrid, row)
+
+	return nil
+}
+
+// addString registers one more reference to str in the shared strings table and returns the index of str there
+func (sc *stringCollection) addString(str string) int {
+	strToSave := utf8toBIFF8UnicodeLong(str)
+	idx, ok := sc.stringMap[strToSave]
+	if !ok {
+		idx = sc.stringUnique
+		sc.stringMap[strToSave] = idx
+		sc.stringList = append(sc.stringList, strToSave)
+		sc.stringUnique++
+	}
+	sc.stringTotal++
+
+	return idx
+}
@@ -0,0 +1,269 @@
+package xls
+
+import (
+	"bytes"
+)
+
+// Office Drawing record types
+const (
+	escherDggContainer     uint16 = 0xF000
+	escherDgContainer      uint16 = 0xF002
+	escherSpgrContainer    uint16 = 0xF003
+	escherSpContainer      uint16 = 0xF004
+	escherDgg              uint16 = 0xF006
+	escherDg               uint16 = 0xF008
+	escherSpgr             uint16 = 0xF009
+	escherSp               uint16 = 0xF00A
+	escherOpt              uint16 = 0xF00B
+	escherClientAnchor     uint16 = 0xF010
+	escherClientTextbox    uint16 = 0xF00D
+	escherClientData       uint16 = 0xF011
+	escherSplitMenuColors  uint16 = 0xF11E
+	escherContainerVersion uint16 = 0x000F
+)
+
+// shapeIdsPerCluster is the number of shape ids in a cluster of the drawing group
+const shapeIdsPerCluster = 1024
+
+// escherRecord writes an Office Drawing record header followed by the data
+func escherRecord(buffer *bytes.Buffer, version, instance, recordType uint16, data []byte) {
+	putVar(buffer, version|instance<<4, recordType, uint32(len(data)), data)
+}
+
+// escherProperty is a property of the OPT record
+type escherProperty struct {
+	id    uint16
+	value uint32
+}
+
+// escherOptions returns the OPT record with the properties
+func escherOptions(properties []escherProperty) []byte {
+	data := new(bytes.Buffer)
+	for _, p := range properties {
+		putVar(data, p.id, p.value)
+	}
+
+	buf := new(bytes.Buffer)
+	escherRecord(buf, 0x3, uint16(len(properties)), escherOpt, data.Bytes())
+
+	return buf.Bytes()
+}
+
+// clientAnchor is the position of a shape, the shape covers the cells from (row1, col1) to (row2, col2).
+// The offsets are in 1/1024 of the column width and in 1/256 of the row height.
+type clientAnchor struct {
+	col1, dx1, row1, dy1 uint16
+	col2, dx2, row2, dy2 uint16
+}
+
+// drawingShape is a shape of the worksheet drawing, every shape is followed by its OBJ record
+type drawingShape struct {
+	shapeType  uint16 // MSOSPT shape type
+	flags      uint32 // FSP flags
+	properties []escherProperty
+	anchor     clientAnchor
+	anchorFlag uint16 // Bit 0 - the shape does not move with the cells, bit 1 - the shape is not sized with the cells
+	obj        func(buffer *bytes.Buffer, objID uint16)
+	textbox    func(buffer *bytes.Buffer) // Writes the TXO record of a text box shape, nil for the shapes without text
+}
+
+// drawing is the patriarch of the shapes of one worksheet
+type drawing struct {
+	dgID   uint32   // 1-based drawing id
+	spIDs  []uint32 // Shape ids of the patriarch and the shapes
+	shapes []drawingShape
+}
+
+// drawingGroup collects the drawings of the worksheets and assigns the shape ids
+type drawingGroup struct {
+	clusters [][2]uint32 // Drawing id and number of used shape ids of every cluster
+	drawings []*drawing
+	blips    []blip // Pictures of the BLIP store
+}
+
+// addDrawing creates the drawing of the shapes, it returns nil when there are no shapes
+func (dg *drawingGroup) addDrawing(shapes []drawingShape) *drawing {
+	if len(shapes) == 0 {
+		return nil
+	}
+
+	d := &drawing{dgID: uint32(len(dg.drawings) + 1), shapes: shapes}
+
+	// The patriarch group shape takes the first shape id, the clusters are numbered from 1
+	for i := 0; i <= len(shapes); i++ {
+		if i%shapeIdsPerCluster == 0 {
+			dg.clusters = append(dg.clusters, [2]uint32{d.dgID, 0})
+		}
+		cluster := len(dg.clusters)
+		d.spIDs = append(d.spIDs, uint32(cluster*shapeIdsPerCluster+i%shapeIdsPerCluster))
+		dg.clusters[cluster-1][1]++
+	}
+	dg.drawings = append(dg.drawings, d)
+
+	return d
+}
+
+// write writes the MSODRAWINGGROUP record
+func (dg *drawingGroup) write(buffer *bytes.Buffer) {
+	if len(dg.drawings) == 0 {
+		return
+	}
+
+	var shapesSaved, spIDMax uint32
+	for _, d := range dg.drawings {
+		shapesSaved += uint32(len(d.spIDs))
+		spIDMax = d.spIDs[len(d.spIDs)-1] + 1
+	}
+
+	dgg := new(bytes.Buffer)
+	putVar(dgg, spIDMax, uint32(len(dg.clusters)+1), shapesSaved, uint32(len(dg.drawings)))
+	for _, cluster := range dg.clusters {
+		putVar(dgg, cluster[0], cluster[1])
+	}
+
+	data := new(bytes.Buffer)
+	escherRecord(data, 0, 0, escherDgg, dgg.Bytes())
+	dg.writeBStore(data)
+	data.Write(escherOptions([]escherProperty{
+		{0x00BF, 0x00080008}, // Fit text to shape
+		{0x0181, 0x08000041}, // Fill color
+		{0x01C0, 0x08000040}, // Line color
+	}))
+	splitMenuColors := new(bytes.Buffer)
+	putVar(splitMenuColors, uint32(0x0800000D), uint32(0x0800000C), uint32(0x08000017), uint32(0x100000F7))
+	escherRecord(data, 0, 4, escherSplitMenuColors, splitMenuColors.Bytes())
+
+	container := new(bytes.Buffer)
+	escherRecord(container, escherContainerVersion, 0, escherDggContainer, data.Bytes())
+
+	// The pictures do not fit into one record, the rest goes into CONTINUE records
+	var record uint16 = 0x00EB
+	for chunk := container.Bytes(); len(chunk) > 0; record = 0x003C {
+		size := min(len(chunk), 8224)
+		putVar(buffer, record, uint16(size), chunk[:size])
+		chunk = chunk[size:]
+	}
+}
+
+// shapeContainer returns the SpContainer of the shape with the shape id spID
+func (s drawingShape) shapeContainer(spID uint32) []byte {
+	data := new(bytes.Buffer)
+
+	sp := new(bytes.Buffer)
+	putVar(sp, spID, s.flags)
+	escherRecord(data, 0x2, s.shapeType, escherSp, sp.Bytes())
+
+	if len(s.properties) != 0 {
+		data.Write(escherOptions(s.properties))
+	}
+
+	anchor := new(bytes.Buffer)
+	a := s.anchor
+	putVar(anchor, a.col1, a.dx1, a.row1, a.dy1, a.col2, a.dx2, a.row2, a.dy2)
+	escherRecord(data, 0, s.anchorFlag, escherClientAnchor, anchor.Bytes())
+	escherRecord(data, 0, 0, escherClientData, nil)
+
+	// The ClientTextbox of a text box goes into its own MSODRAWING record after the OBJ record, the container length includes it
+	length := data.Len()
+	if s.textbox != nil {
+		length += 8
+	}
+
+	buf := new(bytes.Buffer)
+	putVar(buf, escherContainerVersion, escherSpContainer, uint32(length), data.Bytes())
+
+	return buf.Bytes()
+}
+
+// length returns the length of the SpContainer of the shape with the ClientTextbox written after the OBJ record
+func (s drawingShape) length(container []byte) int {
+	if s.textbox != nil {
+		return len(container) + 8
+	}
+	return len(container)
+}
+
+// write writes the MSODRAWING records of the drawing, each shape is followed by its OBJ record
+func (d *drawing) write(buffer *bytes.Buffer) {
+	// The patriarch group shape
+	patriarch := new(bytes.Buffer)
+	escherRecord(patriarch, 0x1, 0, escherSpgr, make([]byte, 16))
+	sp := new(bytes.Buffer)
+	putVar(sp, d.spIDs[0], uint32(0x0005)) // Group and patriarch
+	escherRecord(patriarch, 0x2, 0, escherSp, sp.Bytes())
+
+	patriarchContainer := new(bytes.Buffer)
+	escherRecord(patriarchContainer, escherContainerVersion, 0, escherSpContainer, patriarch.Bytes())
+
+	shapeContainers := make([][]byte, 0, len(d.shapes))
+	groupLength := patriarchContainer.Len()
+	for i, shape := range d.shapes {
+		shapeContainers = append(shapeContainers, shape.shapeContainer(d.spIDs[i+1]))
+		groupLength += shape.length(shapeContainers[i])
+	}
+
+	// The containers are split into MSODRAWING records, their lengths include the shapes of the next records
+	header := new(bytes.Buffer)
+	dg := new(bytes.Buffer)
+	putVar(dg, uint32(len(d.spIDs)), d.spIDs[len(d.spIDs)-1])
+	dgLength := 16 + 8 + groupLength
+	putVar(header, escherContainerVersion, escherDgContainer, uint32(dgLength))
+	escherRecord(header, 0, uint16(d.dgID), escherDg, dg.Bytes())
+	putVar(header, escherContainerVersion, escherSpgrContainer, uint32(groupLength))
+	header.Write(patriarchContainer.Bytes())
+
+	for i, shape := range d.shapes {
+		data := shapeContainers[i]
+		if i == 0 {
+			data = append(header.Bytes(), data...)
+		}
+
+		putVar(buffer, uint16(0x00EC), uint16(len(data)), data)
+
+		shape.obj(buffer, uint16(i+1))
+
+		if shape.textbox != nil {
+			textbox := new(bytes.Buffer)
+			escherRecord(textbox, 0, 0, escherClientTextbox, nil)
+			putVar(buffer, uint16(0x00EC), uint16(textbox.Len()), textbox.Bytes())
+			shape.textbox(buffer)
+		}
+	}
+}
+
+// objFilterDropDown writes the OBJ record of an autofilter drop-down
+func objFilterDropDown(buffer *bytes.Buffer, objID uint16) {
+	var record uint16 = 0x005D // Record identifier
+	var length uint16 = 0x0046 // Bytes to follow
+
+	putVar(buffer, record, length)
+
+	// ftCmo: combo box
+	putVar(buffer, uint16(0x0015), uint16(0x0012), uint16(0x0014), objID, uint16(0x2101), uint32(0), uint32(0), uint32(0))
+	// ftSbs: scroll bar
+	putVar(buffer, uint16(0x000C), uint16(0x0014))
+	putVar(buffer, uint32(0), uint16(0), uint16(0), uint16(0x0064), uint16(0x0001), uint16(0x000A), uint16(0), uint16(0x0010), uint16(0x0001))
+	// ftLbsData: drop-down list of an autofilter
+	putVar(buffer, uint16(0x0013), uint16(0x1FEE))
+	putVar(buffer, uint16(0), uint16(0), uint16(0x0001), uint16(0x0301), uint16(0), uint16(0x0002), uint16(0x0008), uint16(0x0057))
+	// ftEnd
+	putVar(buffer, uint16(0x0000), uint16(0x0000))
+}
+
+// filterDropDown returns the shape of the autofilter drop-down in the cell (row, column)
+func filterDropDown(row, column int) drawingShape {
+	return drawingShape{
+		shapeType: 201, // Host control
+		flags:     0x0A00,
+		properties: []escherProperty{
+			{0x007F, 0x01040104}, // Lock against grouping
+			{0x00BF, 0x00080008}, // Fit text to shape
+			{0x01BF, 0x00010000}, // No fill hit test
+			{0x01FF, 0x00080000}, // No line
+			{0x03BF, 0x00020000}, // Not printed
+		},
+		anchor:     clientAnchor{uint16(column), 0, uint16(row), 0, uint16(column + 1), 0, uint16(row + 1), 0},
+		anchorFlag: 1,
+		obj:        objFilterDropDown,
+	}
+}
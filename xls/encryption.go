@@ -0,0 +1,153 @@
+package xls
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// maxOpenPasswordLength is the longest password to open of the RC4 encryption
+const maxOpenPasswordLength = 255
+
+// rc4BlockSize is the number of stream bytes encrypted with one RC4 key
+const rc4BlockSize = 1024
+
+// unencryptedRecords are the records written as plain text into the encrypted workbook stream
+var unencryptedRecords = map[uint16]bool{
+	0x0809: true, // BOF
+	0x002F: true, // FILEPASS
+	0x0194: true, // USREXCL
+	0x0195: true, // FILELOCK
+	0x00E1: true, // INTERFACEHDR
+	0x0196: true, // RRDINFO
+	0x0138: true, // RRDHEAD
+}
+
+// rc4Encryption is the Office binary document RC4 encryption of the workbook stream
+type rc4Encryption struct {
+	salt                  []byte
+	encryptedVerifier     []byte
+	encryptedVerifierHash []byte
+	truncatedHash         []byte // First 40 bits of the hash of the password and the salt
+}
+
+// newRC4Encryption derives the encryption key from the password with a random salt
+func newRC4Encryption(password string) (*rc4Encryption, error) {
+	salt := make([]byte, 16)
+	verifier := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(verifier); err != nil {
+		return nil, err
+	}
+
+	return newRC4EncryptionWithSalt(password, salt, verifier)
+}
+
+// newRC4EncryptionWithSalt derives the encryption key from the password with the 16-byte salt and encrypts the 16-byte verifier
+func newRC4EncryptionWithSalt(password string, salt, verifier []byte) (*rc4Encryption, error) {
+	// The password is hashed as UTF-16LE
+	passwordBytes := new(bytes.Buffer)
+	putVar(passwordBytes, utf16.Encode([]rune(password)))
+	passwordHash := md5.Sum(passwordBytes.Bytes())
+
+	intermediate := new(bytes.Buffer)
+	for i := 0; i < 16; i++ {
+		intermediate.Write(passwordHash[:5])
+		intermediate.Write(salt)
+	}
+	hash := md5.Sum(intermediate.Bytes())
+
+	e := &rc4Encryption{salt: salt, truncatedHash: hash[:5]}
+
+	// The verifier and its hash are encrypted with one key stream of the block 0
+	cipher, err := rc4.NewCipher(e.blockKey(0))
+	if err != nil {
+		return nil, err
+	}
+	verifierHash := md5.Sum(verifier)
+	e.encryptedVerifier = make([]byte, 16)
+	e.encryptedVerifierHash = make([]byte, 16)
+	cipher.XORKeyStream(e.encryptedVerifier, verifier)
+	cipher.XORKeyStream(e.encryptedVerifierHash, verifierHash[:])
+
+	return e, nil
+}
+
+// blockKey returns the RC4 key of the block of the stream
+func (e *rc4Encryption) blockKey(block uint32) []byte {
+	data := new(bytes.Buffer)
+	putVar(data, e.truncatedHash, block)
+	key := md5.Sum(data.Bytes())
+
+	return key[:]
+}
+
+// writeFilePass writes the FILEPASS record
+func (e *rc4Encryption) writeFilePass(buffer *bytes.Buffer) {
+	var record uint16 = 0x002F // Record identifier
+	var length uint16 = 0x0036 // Bytes to follow
+
+	var wEncryptionType uint16 = 0x0001 // RC4
+	var vMajor uint16 = 0x0001
+	var vMinor uint16 = 0x0001
+
+	putVar(buffer, record, length)
+	putVar(buffer, wEncryptionType, vMajor, vMinor, e.salt, e.encryptedVerifier, e.encryptedVerifierHash)
+}
+
+// encryptStream encrypts the record data of the workbook stream in place.
+// The key stream follows the stream offsets, so the record headers and the plain text records consume it too.
+func (e *rc4Encryption) encryptStream(stream []byte) error {
+	keyStream := make([]byte, rc4BlockSize)
+	block := -1
+
+	xor := func(from, to int) error {
+		for i := from; i < to; i++ {
+			if i/rc4BlockSize != block {
+				block = i / rc4BlockSize
+				cipher, err := rc4.NewCipher(e.blockKey(uint32(block)))
+				if err != nil {
+					return err
+				}
+				for j := range keyStream {
+					keyStream[j] = 0
+				}
+				cipher.XORKeyStream(keyStream, keyStream)
+			}
+			stream[i] ^= keyStream[i%rc4BlockSize]
+		}
+		return nil
+	}
+
+	for pos := 0; pos+4 <= len(stream); {
+		record := binary.LittleEndian.Uint16(stream[pos:])
+		length := int(binary.LittleEndian.Uint16(stream[pos+2:]))
+		start, end := pos+4, pos+4+length
+		if end > len(stream) {
+			return fmt.Errorf("record 0x%04X overflows the workbook stream", record)
+		}
+
+		switch {
+		case unencryptedRecords[record]:
+		case record == 0x0085:
+			// The BOUNDSHEET stream position of the worksheet stays plain text
+			if err := xor(start+4, end); err != nil {
+				return err
+			}
+		default:
+			if err := xor(start, end); err != nil {
+				return err
+			}
+		}
+
+		pos = end
+	}
+
+	return nil
+}
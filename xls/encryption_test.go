@@ -0,0 +1,64 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// testEncryption returns the RC4 encryption of the password "secret" with the salt 00 01 ... 0F and the verifier 10 11 ... 1F
+func testEncryption(t *testing.T) *rc4Encryption {
+	salt := make([]byte, 16)
+	verifier := make([]byte, 16)
+	for i := range salt {
+		salt[i] = byte(i)
+		verifier[i] = byte(16 + i)
+	}
+
+	e, err := newRC4EncryptionWithSalt("secret", salt, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestRC4EncryptionVerifier(t *testing.T) {
+	e := testEncryption(t)
+
+	if got := hex.EncodeToString(e.encryptedVerifier); got != "1c588a9d32e314f5f10534aa44266cb2" {
+		t.Errorf("encrypted verifier = %s", got)
+	}
+	if got := hex.EncodeToString(e.encryptedVerifierHash); got != "affa6e6f1ad31daa7bb21bb4ed275590" {
+		t.Errorf("encrypted verifier hash = %s", got)
+	}
+}
+
+func TestRC4EncryptStream(t *testing.T) {
+	e := testEncryption(t)
+
+	stream := new(bytes.Buffer)
+	putVar(stream, uint16(0x0809), uint16(16), make([]byte, 16))                   // BOF, offsets 0 - 19
+	putVar(stream, uint16(0x0085), uint16(8), uint32(0x11223344), make([]byte, 4)) // BOUNDSHEET, offsets 20 - 31
+	putVar(stream, uint16(0x0204), uint16(2000), make([]byte, 2000))               // LABEL crossing the block boundary, offsets 32 - 2035
+	data := stream.Bytes()
+	if err := e.encryptStream(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data[:20], append([]byte{0x09, 0x08, 0x10, 0x00}, make([]byte, 16)...)) {
+		t.Errorf("BOF = % x, want plain text", data[:20])
+	}
+	if got := hex.EncodeToString(data[20:28]); got != "8500080044332211" {
+		t.Errorf("BOUNDSHEET header and lbPlyPos = %s, want plain text", got)
+	}
+	if got := hex.EncodeToString(data[28:32]); got != "5c9b4886" {
+		t.Errorf("BOUNDSHEET rest = %s", got)
+	}
+	if got := hex.EncodeToString(data[32:36]); got != "0402d007" {
+		t.Errorf("LABEL header = %s, want plain text", got)
+	}
+	// The bytes 1020 - 1023 use the key of the block 0, the bytes 1024 - 1027 the key of the block 1
+	if got := hex.EncodeToString(data[1020:1028]); got != "779af7d01f483d92" {
+		t.Errorf("bytes around the block boundary = %s", got)
+	}
+}
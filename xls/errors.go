@@ -0,0 +1,126 @@
+package xls
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidDelimiter is returned when the csv delimiter is not a single character
+	ErrInvalidDelimiter = errors.New("csv delimiter must be one character string")
+	// ErrTooManyColumns is returned when a csv row does not fit into the 256 columns of a BIFF8 worksheet
+	ErrTooManyColumns = errors.New("columns overflow: Excel5 has limit to 256 columns, use XLSX instead")
+	// ErrTooManyWorksheets is returned when the csv data does not fit into 255 worksheets
+	ErrTooManyWorksheets = errors.New("worksheets overflow: Excel5 has limit to 255 worksheets")
+	// ErrInvalidColumn is returned when a column reference is neither a column letter nor a 1-based column number
+	ErrInvalidColumn = errors.New("invalid column")
+	// ErrInvalidColumnType is returned when a column type name is unknown
+	ErrInvalidColumnType = errors.New("invalid column type")
+	// ErrInvalidNumberSeparators is returned when the decimal separator is empty or a digit, or equals the thousands separator
+	ErrInvalidNumberSeparators = errors.New("invalid number separators")
+	// ErrInvalidHeaderRows is returned when the number of header rows does not fit into a worksheet
+	ErrInvalidHeaderRows = errors.New("header rows must be between 0 and 65534")
+	// ErrInvalidColumnWidth is returned when a column width is out of 0 - 255 characters
+	ErrInvalidColumnWidth = errors.New("column width must be between 0 and 255")
+	// ErrInvalidSheet is returned when a worksheet name is not one of "worksheet", "worksheet1", ... "worksheet254"
+	ErrInvalidSheet = errors.New("invalid worksheet")
+	// ErrInvalidCell is returned when a cell reference is not like "B2"
+	ErrInvalidCell = errors.New("invalid cell")
+	// ErrInvalidRange is returned when a cell range is not like "A1:D1" or does not fit into a worksheet
+	ErrInvalidRange = errors.New("invalid cell range")
+	// ErrOverlappingMergedCells is returned when the merged ranges of a worksheet overlap
+	ErrOverlappingMergedCells = errors.New("merged cells overlap")
+	// ErrInvalidDataValidation is returned when a data validation rule is malformed
+	ErrInvalidDataValidation = errors.New("invalid data validation")
+	// ErrInvalidPane is returned when a freeze or split position does not fit into a worksheet
+	ErrInvalidPane = errors.New("pane position out of range")
+	// ErrInvalidSheetPermission is returned when a sheet permission name is unknown
+	ErrInvalidSheetPermission = errors.New("invalid sheet permission")
+	// ErrInvalidPassword is returned when a protection password cannot be hashed
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrInvalidPageSetup is returned when a page setup is malformed or out of the range Excel accepts
+	ErrInvalidPageSetup = errors.New("invalid page setup")
+	// ErrInvalidHeaderFooter is returned when a page header or footer has an unknown &-code or is longer than 255 characters
+	ErrInvalidHeaderFooter = errors.New("invalid page header or footer")
+	// ErrInvalidNote is returned when a cell note is out of the worksheet, its text is empty or longer than 32767 characters
+	ErrInvalidNote = errors.New("invalid note")
+	// ErrInvalidImage is returned when an image is neither PNG nor JPEG or is out of the worksheet
+	ErrInvalidImage = errors.New("invalid image")
+	// ErrInvalidChart is returned when a chart has an unknown type, no series or is out of the worksheet
+	ErrInvalidChart = errors.New("invalid chart")
+	// ErrInvalidProperty is returned when a custom document property has an empty or too long name or an unsupported value
+	ErrInvalidProperty = errors.New("invalid custom property")
+	// ErrSanitizeFormulas is returned when the formulas are enabled together with a sanitization policy
+	ErrSanitizeFormulas = errors.New("formulas cannot be combined with sanitization")
+	// ErrInvalidSanitizePolicy is returned when a sanitize policy name is unknown
+	ErrInvalidSanitizePolicy = errors.New("invalid sanitize policy")
+)
+
+// CsvReadError is returned when the csv input cannot be opened or parsed
+type CsvReadError struct {
+	FileName string
+	Err      error
+}
+
+// Error ...
+func (e *CsvReadError) Error() string {
+	if e.FileName == "" {
+		return fmt.Sprintf("cannot read csv data: %v", e.Err)
+	}
+	return fmt.Sprintf(`cannot read csv file "%s": %v`, e.FileName, e.Err)
+}
+
+// Unwrap ...
+func (e *CsvReadError) Unwrap() error {
+	return e.Err
+}
+
+// XlsWriteError is returned when the xls output cannot be created or written
+type XlsWriteError struct {
+	FileName string
+	Err      error
+}
+
+// Error ...
+func (e *XlsWriteError) Error() string {
+	if e.FileName == "" {
+		return fmt.Sprintf("cannot write xls data: %v", e.Err)
+	}
+	return fmt.Sprintf(`cannot write xls file "%s": %v`, e.FileName, e.Err)
+}
+
+// Unwrap ...
+func (e *XlsWriteError) Unwrap() error {
+	return e.Err
+}
+
+// FormulaError is returned when a csv value starting with "=" is not a valid formula
+type FormulaError struct {
+	Sheet   string
+	Row     int // 0-based row index in the sheet
+	Column  int // 0-based column index
+	Formula string
+	Err     error
+}
+
+// Error ...
+func (e *FormulaError) Error() string {
+	return fmt.Sprintf(`invalid formula "%s" in %s!%s: %v`, e.Formula, e.Sheet, cellName(e.Row, e.Column), e.Err)
+}
+
+// Unwrap ...
+func (e *FormulaError) Unwrap() error {
+	return e.Err
+}
+
+// SanitizeError is returned by SanitizeReject policy for a csv value that Excel could run as a formula
+type SanitizeError struct {
+	Row    int // 0-based csv row index
+	Column int // 0-based column index
+	Value  string
+}
+
+// Error ...
+func (e *SanitizeError) Error() string {
+	return fmt.Sprintf(`unsafe csv value "%s" in row %d column %s`, e.Value, e.Row+1, columnName(e.Column))
+}
@@ -0,0 +1,629 @@
+package xls
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parsed tokens (ptg) of the BIFF8 formulas
+const (
+	ptgAdd     = 0x03
+	ptgSub     = 0x04
+	ptgMul     = 0x05
+	ptgDiv     = 0x06
+	ptgPower   = 0x07
+	ptgConcat  = 0x08
+	ptgLT      = 0x09
+	ptgLE      = 0x0A
+	ptgEQ      = 0x0B
+	ptgGE      = 0x0C
+	ptgGT      = 0x0D
+	ptgNE      = 0x0E
+	ptgUnion   = 0x10
+	ptgUplus   = 0x12
+	ptgUminus  = 0x13
+	ptgPercent = 0x14
+	ptgParen   = 0x15
+	ptgMissArg = 0x16
+	ptgStr     = 0x17
+	ptgErr     = 0x1C
+	ptgBool    = 0x1D
+	ptgInt     = 0x1E
+	ptgNum     = 0x1F
+	ptgRef     = 0x24 // Reference class tokens, functions like SUM get the reference itself
+	ptgArea    = 0x25
+	ptgMemFunc = 0x29 // Reference expression, its length goes first
+	ptgRef3d   = 0x3A
+	ptgArea3d  = 0x3B
+	ptgFuncV   = 0x41
+	ptgFuncVar = 0x42
+	ptgRefV    = 0x44 // Value class tokens, operators get the value of the cell
+	ptgRef3dV  = 0x5A
+)
+
+// maxFormulaLength is the longest parsed formula Excel accepts, in bytes
+const maxFormulaLength = 1800
+
+// formulaFunction describes a built-in function: its index in the Excel function table and the number of arguments
+type formulaFunction struct {
+	index   uint16
+	minArgs int
+	maxArgs int
+	fixed   bool // Fixed number of arguments, written as ptgFunc instead of ptgFuncVar
+}
+
+func fixedFunction(index uint16, args int) formulaFunction {
+	return formulaFunction{index, args, args, true}
+}
+
+func varFunction(index uint16, minArgs, maxArgs int) formulaFunction {
+	return formulaFunction{index, minArgs, maxArgs, false}
+}
+
+// formulaFunctions is the subset of the Excel functions supported in formulas
+var formulaFunctions = map[string]formulaFunction{
+	"ABS":         fixedFunction(24, 1),
+	"AND":         varFunction(36, 1, 30),
+	"AVERAGE":     varFunction(5, 1, 30),
+	"AVERAGEA":    varFunction(361, 1, 30),
+	"CEILING":     fixedFunction(288, 2),
+	"CHOOSE":      varFunction(100, 2, 30),
+	"COLUMN":      varFunction(9, 0, 1),
+	"CONCATENATE": varFunction(336, 1, 30),
+	"COUNT":       varFunction(0, 1, 30),
+	"COUNTA":      varFunction(169, 1, 30),
+	"COUNTBLANK":  fixedFunction(347, 1),
+	"COUNTIF":     fixedFunction(346, 2),
+	"DATE":        fixedFunction(65, 3),
+	"DAY":         fixedFunction(67, 1),
+	"EXACT":       fixedFunction(117, 2),
+	"EXP":         fixedFunction(21, 1),
+	"FIND":        varFunction(124, 2, 3),
+	"FLOOR":       fixedFunction(285, 2),
+	"HLOOKUP":     varFunction(101, 3, 4),
+	"HOUR":        fixedFunction(71, 1),
+	"IF":          varFunction(1, 2, 3),
+	"INDEX":       varFunction(29, 2, 4),
+	"INT":         fixedFunction(25, 1),
+	"ISBLANK":     fixedFunction(129, 1),
+	"ISERROR":     fixedFunction(3, 1),
+	"ISNA":        fixedFunction(2, 1),
+	"ISNUMBER":    fixedFunction(128, 1),
+	"ISTEXT":      fixedFunction(127, 1),
+	"LEFT":        varFunction(115, 1, 2),
+	"LEN":         fixedFunction(32, 1),
+	"LN":          fixedFunction(22, 1),
+	"LOG10":       fixedFunction(23, 1),
+	"LOOKUP":      varFunction(28, 2, 3),
+	"LOWER":       fixedFunction(112, 1),
+	"MATCH":       varFunction(64, 2, 3),
+	"MAX":         varFunction(7, 1, 30),
+	"MAXA":        varFunction(362, 1, 30),
+	"MEDIAN":      varFunction(227, 1, 30),
+	"MID":         fixedFunction(31, 3),
+	"MIN":         varFunction(6, 1, 30),
+	"MINA":        varFunction(363, 1, 30),
+	"MINUTE":      fixedFunction(72, 1),
+	"MOD":         fixedFunction(39, 2),
+	"MONTH":       fixedFunction(68, 1),
+	"NA":          fixedFunction(10, 0),
+	"NOT":         fixedFunction(38, 1),
+	"NOW":         fixedFunction(74, 0),
+	"OR":          varFunction(37, 1, 30),
+	"PI":          fixedFunction(19, 0),
+	"POWER":       fixedFunction(337, 2),
+	"PRODUCT":     varFunction(183, 1, 30),
+	"PROPER":      fixedFunction(114, 1),
+	"REPT":        fixedFunction(30, 2),
+	"RIGHT":       varFunction(116, 1, 2),
+	"ROUND":       fixedFunction(27, 2),
+	"ROUNDDOWN":   fixedFunction(213, 2),
+	"ROUNDUP":     fixedFunction(212, 2),
+	"ROW":         varFunction(8, 0, 1),
+	"SEARCH":      varFunction(82, 2, 3),
+	"SECOND":      fixedFunction(73, 1),
+	"SIGN":        fixedFunction(26, 1),
+	"SQRT":        fixedFunction(20, 1),
+	"SUBSTITUTE":  varFunction(120, 3, 4),
+	"SUM":         varFunction(4, 1, 30),
+	"SUMIF":       varFunction(345, 2, 3),
+	"SUMPRODUCT":  varFunction(228, 1, 30),
+	"SUMSQ":       varFunction(321, 1, 30),
+	"TEXT":        fixedFunction(48, 2),
+	"TIME":        fixedFunction(66, 3),
+	"TODAY":       fixedFunction(221, 0),
+	"TRIM":        fixedFunction(118, 1),
+	"UPPER":       fixedFunction(113, 1),
+	"VALUE":       fixedFunction(33, 1),
+	"VLOOKUP":     varFunction(102, 3, 4),
+	"WEEKDAY":     varFunction(70, 1, 2),
+	"YEAR":        fixedFunction(69, 1),
+}
+
+var (
+	formulaCellRe     = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})(\$?)([0-9]{1,5})`)
+	formulaColumnRe   = regexp.MustCompile(`^(\$?)([A-Za-z]{1,3})`)
+	formulaRowRe      = regexp.MustCompile(`^(\$?)([0-9]{1,5})`)
+	formulaRowRangeRe = regexp.MustCompile(`^[0-9]+:\$?[0-9]`)
+	formulaNumberRe   = regexp.MustCompile(`^([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?`)
+	formulaNameRe     = regexp.MustCompile(`^[A-Za-z_\\][A-Za-z0-9_.$]*`)
+)
+
+// formulaCompiler converts a formula into BIFF8 parsed tokens in reverse polish notation
+type formulaCompiler struct {
+	src        string
+	pos        int
+	out        *bytes.Buffer
+	sheetNames []string
+}
+
+// compileFormula compiles formula, which is written without the leading "=".
+// sheetNames are the names of the worksheets in the EXTERNSHEET order, they resolve the cross-sheet references.
+func compileFormula(formula string, sheetNames []string) ([]byte, error) {
+	fc := &formulaCompiler{src: formula, out: new(bytes.Buffer), sheetNames: sheetNames}
+	if err := fc.parseExpression(); err != nil {
+		return nil, err
+	}
+	fc.skipSpaces()
+	if fc.pos < len(fc.src) {
+		return nil, fc.errorf("unexpected %q", fc.src[fc.pos:])
+	}
+	if fc.out.Len() > maxFormulaLength {
+		return nil, fmt.Errorf("parsed formula is longer than %d bytes", maxFormulaLength)
+	}
+
+	return fc.out.Bytes(), nil
+}
+
+func (fc *formulaCompiler) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at position %d: %s", fc.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (fc *formulaCompiler) skipSpaces() {
+	for fc.pos < len(fc.src) && fc.src[fc.pos] == ' ' {
+		fc.pos++
+	}
+}
+
+// accept consumes the first of the operators the rest of the formula starts with
+func (fc *formulaCompiler) accept(operators ...string) string {
+	fc.skipSpaces()
+	for _, op := range operators {
+		if strings.HasPrefix(fc.src[fc.pos:], op) {
+			fc.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// parseExpression parses the comparison operators, they have the lowest priority
+func (fc *formulaCompiler) parseExpression() error {
+	tokens := map[string]uint8{"<>": ptgNE, "<=": ptgLE, ">=": ptgGE, "<": ptgLT, ">": ptgGT, "=": ptgEQ}
+
+	if err := fc.parseConcat(); err != nil {
+		return err
+	}
+	for op := fc.accept("<>", "<=", ">=", "<", ">", "="); op != ""; op = fc.accept("<>", "<=", ">=", "<", ">", "=") {
+		if err := fc.parseConcat(); err != nil {
+			return err
+		}
+		putVar(fc.out, tokens[op])
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parseConcat() error {
+	if err := fc.parseAdditive(); err != nil {
+		return err
+	}
+	for fc.accept("&") != "" {
+		if err := fc.parseAdditive(); err != nil {
+			return err
+		}
+		putVar(fc.out, uint8(ptgConcat))
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parseAdditive() error {
+	if err := fc.parseMultiplicative(); err != nil {
+		return err
+	}
+	for op := fc.accept("+", "-"); op != ""; op = fc.accept("+", "-") {
+		if err := fc.parseMultiplicative(); err != nil {
+			return err
+		}
+		if op == "+" {
+			putVar(fc.out, uint8(ptgAdd))
+		} else {
+			putVar(fc.out, uint8(ptgSub))
+		}
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parseMultiplicative() error {
+	if err := fc.parsePower(); err != nil {
+		return err
+	}
+	for op := fc.accept("*", "/"); op != ""; op = fc.accept("*", "/") {
+		if err := fc.parsePower(); err != nil {
+			return err
+		}
+		if op == "*" {
+			putVar(fc.out, uint8(ptgMul))
+		} else {
+			putVar(fc.out, uint8(ptgDiv))
+		}
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parsePower() error {
+	if err := fc.parsePercent(); err != nil {
+		return err
+	}
+	for fc.accept("^") != "" {
+		if err := fc.parsePercent(); err != nil {
+			return err
+		}
+		putVar(fc.out, uint8(ptgPower))
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parsePercent() error {
+	if err := fc.parseUnary(); err != nil {
+		return err
+	}
+	for fc.accept("%") != "" {
+		putVar(fc.out, uint8(ptgPercent))
+	}
+
+	return nil
+}
+
+// parseUnary parses the unary minus and plus, in Excel they go before the other operators, so -2^2 is 4
+func (fc *formulaCompiler) parseUnary() error {
+	switch fc.accept("-", "+") {
+	case "-":
+		if err := fc.parseUnary(); err != nil {
+			return err
+		}
+		putVar(fc.out, uint8(ptgUminus))
+		return nil
+	case "+":
+		if err := fc.parseUnary(); err != nil {
+			return err
+		}
+		putVar(fc.out, uint8(ptgUplus))
+		return nil
+	}
+
+	return fc.parsePrimary()
+}
+
+func (fc *formulaCompiler) parsePrimary() error {
+	fc.skipSpaces()
+	if fc.pos >= len(fc.src) {
+		return fc.errorf("unexpected end of formula")
+	}
+
+	rest := fc.src[fc.pos:]
+	switch {
+	case rest[0] == '(':
+		fc.pos++
+		if err := fc.parseExpression(); err != nil {
+			return err
+		}
+		if fc.accept(")") == "" {
+			return fc.errorf("missing closing parenthesis")
+		}
+		putVar(fc.out, uint8(ptgParen))
+		return nil
+	case rest[0] == '"':
+		return fc.parseString()
+	case rest[0] == '#':
+		return fc.parseError()
+	case rest[0] == '\'':
+		return fc.parseQuotedSheetReference()
+	case rest[0] == '$':
+		return fc.parseReference(-1)
+	case rest[0] == '.' || (rest[0] >= '0' && rest[0] <= '9'):
+		if formulaRowRangeRe.MatchString(rest) {
+			return fc.parseReference(-1)
+		}
+		return fc.parseNumber()
+	}
+
+	name := formulaNameRe.FindString(rest)
+	if name == "" {
+		return fc.errorf("unexpected %q", rest)
+	}
+	after := strings.TrimLeft(rest[len(name):], " ")
+	switch {
+	case strings.HasPrefix(after, "("):
+		return fc.parseFunction(name)
+	case strings.HasPrefix(after, "!"):
+		fc.pos += len(name)
+		fc.accept("!")
+		return fc.parseSheetReference(name)
+	case strings.EqualFold(name, "TRUE") || strings.EqualFold(name, "FALSE"):
+		fc.pos += len(name)
+		var value uint8
+		if strings.EqualFold(name, "TRUE") {
+			value = 1
+		}
+		putVar(fc.out, uint8(ptgBool), value)
+		return nil
+	}
+
+	return fc.parseReference(-1)
+}
+
+func (fc *formulaCompiler) parseString() error {
+	var str strings.Builder
+	fc.pos++ // opening quote
+	for {
+		idx := strings.IndexByte(fc.src[fc.pos:], '"')
+		if idx < 0 {
+			return fc.errorf("missing closing quote")
+		}
+		str.WriteString(fc.src[fc.pos : fc.pos+idx])
+		fc.pos += idx + 1
+		// doubled quote is an escaped quote
+		if fc.pos < len(fc.src) && fc.src[fc.pos] == '"' {
+			str.WriteByte('"')
+			fc.pos++
+			continue
+		}
+		break
+	}
+
+	value := str.String()
+	if utf16Length(value) > 255 {
+		return fc.errorf("string constant is longer than 255 characters")
+	}
+	putVar(fc.out, uint8(ptgStr), []byte(utf8toBIFF8UnicodeShort(value)))
+
+	return nil
+}
+
+func (fc *formulaCompiler) parseError() error {
+	rest := strings.ToUpper(fc.src[fc.pos:])
+	for name, code := range errorCodes {
+		if strings.HasPrefix(rest, name) {
+			fc.pos += len(name)
+			putVar(fc.out, uint8(ptgErr), code)
+			return nil
+		}
+	}
+
+	return fc.errorf("unknown error value")
+}
+
+func (fc *formulaCompiler) parseNumber() error {
+	literal := formulaNumberRe.FindString(fc.src[fc.pos:])
+	num, err := strconv.ParseFloat(literal, 64)
+	if literal == "" || err != nil {
+		return fc.errorf("invalid number")
+	}
+	fc.pos += len(literal)
+
+	if num == math.Trunc(num) && num >= 0 && num <= 0xFFFF {
+		putVar(fc.out, uint8(ptgInt), uint16(num))
+	} else {
+		putVar(fc.out, uint8(ptgNum), num)
+	}
+
+	return nil
+}
+
+func (fc *formulaCompiler) parseFunction(name string) error {
+	function, ok := formulaFunctions[strings.ToUpper(name)]
+	if !ok {
+		return fc.errorf("unsupported function %s", name)
+	}
+	fc.pos += len(name)
+	fc.accept("(")
+
+	args := 0
+	if fc.accept(")") == "" {
+		for {
+			fc.skipSpaces()
+			argStart := fc.out.Len()
+			if fc.pos < len(fc.src) && (fc.src[fc.pos] == ',' || fc.src[fc.pos] == ')') {
+				putVar(fc.out, uint8(ptgMissArg))
+			} else if err := fc.parseExpression(); err != nil {
+				return err
+			}
+			fc.toReferenceClass(argStart)
+			args++
+
+			if fc.accept(",") != "" {
+				continue
+			}
+			if fc.accept(")") != "" {
+				break
+			}
+			return fc.errorf("missing closing parenthesis of %s", name)
+		}
+	}
+
+	if args < function.minArgs || args > function.maxArgs {
+		return fc.errorf("wrong number of arguments of %s", name)
+	}
+
+	if function.fixed {
+		putVar(fc.out, uint8(ptgFuncV), function.index)
+	} else {
+		putVar(fc.out, uint8(ptgFuncVar), uint8(args), function.index)
+	}
+
+	return nil
+}
+
+// toReferenceClass turns a function argument that is a single cell reference into the reference class,
+// so functions like SUM get the reference instead of the cell value
+func (fc *formulaCompiler) toReferenceClass(argStart int) {
+	arg := fc.out.Bytes()[argStart:]
+	if len(arg) == 5 && arg[0] == ptgRefV {
+		arg[0] = ptgRef
+	} else if len(arg) == 7 && arg[0] == ptgRef3dV {
+		arg[0] = ptgRef3d
+	}
+}
+
+func (fc *formulaCompiler) parseQuotedSheetReference() error {
+	var name strings.Builder
+	fc.pos++ // opening quote
+	for {
+		idx := strings.IndexByte(fc.src[fc.pos:], '\'')
+		if idx < 0 {
+			return fc.errorf("missing closing quote of sheet name")
+		}
+		name.WriteString(fc.src[fc.pos : fc.pos+idx])
+		fc.pos += idx + 1
+		// doubled quote is an escaped quote
+		if fc.pos < len(fc.src) && fc.src[fc.pos] == '\'' {
+			name.WriteByte('\'')
+			fc.pos++
+			continue
+		}
+		break
+	}
+	if fc.accept("!") == "" {
+		return fc.errorf("missing ! after sheet name")
+	}
+
+	return fc.parseSheetReference(name.String())
+}
+
+func (fc *formulaCompiler) parseSheetReference(sheetName string) error {
+	for i, name := range fc.sheetNames {
+		if strings.EqualFold(name, sheetName) {
+			return fc.parseReference(i)
+		}
+	}
+
+	return fc.errorf("unknown sheet %s", sheetName)
+}
+
+// parseReference parses a cell, an area, a whole columns or a whole rows reference.
+// ixti is the EXTERNSHEET index of the referenced sheet, -1 for the current sheet.
+func (fc *formulaCompiler) parseReference(ixti int) error {
+	fc.skipSpaces()
+	rest := fc.src[fc.pos:]
+
+	if m := formulaCellRe.FindStringSubmatch(rest); m != nil {
+		first, err := newCellAddress(m)
+		if err != nil {
+			return fc.errorf("%s", err)
+		}
+		fc.pos += len(m[0])
+
+		if strings.HasPrefix(fc.src[fc.pos:], ":") {
+			if m := formulaCellRe.FindStringSubmatch(fc.src[fc.pos+1:]); m != nil {
+				last, err := newCellAddress(m)
+				if err != nil {
+					return fc.errorf("%s", err)
+				}
+				fc.pos += 1 + len(m[0])
+				fc.writeArea(ixti, first, last)
+				return nil
+			}
+		}
+
+		fc.writeRef(ixti, first)
+		return nil
+	}
+
+	if loc := strings.IndexByte(rest, ':'); loc > 0 {
+		left, right := rest[:loc], rest[loc+1:]
+		if formulaColumnRe.FindString(left) == left {
+			if m := formulaColumnRe.FindStringSubmatch(right); m != nil && !formulaCellRe.MatchString(right) {
+				first, err1 := newCellAddress([]string{"", formulaColumnRe.FindStringSubmatch(left)[1], formulaColumnRe.FindStringSubmatch(left)[2], "$", "1"})
+				last, err2 := newCellAddress([]string{"", m[1], m[2], "$", "65536"})
+				if err1 == nil && err2 == nil {
+					fc.pos += loc + 1 + len(m[0])
+					fc.writeArea(ixti, first, last)
+					return nil
+				}
+			}
+		}
+		if formulaRowRe.FindString(left) == left {
+			if m := formulaRowRe.FindStringSubmatch(right); m != nil {
+				first, err1 := newCellAddress([]string{"", "$", "A", formulaRowRe.FindStringSubmatch(left)[1], formulaRowRe.FindStringSubmatch(left)[2]})
+				last, err2 := newCellAddress([]string{"", "$", "IV", m[1], m[2]})
+				if err1 == nil && err2 == nil {
+					fc.pos += loc + 1 + len(m[0])
+					fc.writeArea(ixti, first, last)
+					return nil
+				}
+			}
+		}
+	}
+
+	return fc.errorf("invalid reference %q", rest)
+}
+
+// cellAddress is a cell of a formula reference
+type cellAddress struct {
+	row         uint16
+	col         uint16
+	rowRelative bool
+	colRelative bool
+}
+
+// newCellAddress creates a cellAddress from the submatches of formulaCellRe
+func newCellAddress(m []string) (cellAddress, error) {
+	col, err := ParseColumn(m[2])
+	if err != nil {
+		return cellAddress{}, err
+	}
+	row, err := strconv.Atoi(m[4])
+	if err != nil || row < 1 || row > 65536 {
+		return cellAddress{}, errors.New("row out of range")
+	}
+
+	return cellAddress{uint16(row - 1), uint16(col), m[3] == "", m[1] == ""}, nil
+}
+
+// colField packs the column index with the relative flags
+func (a cellAddress) colField() uint16 {
+	col := a.col
+	if a.colRelative {
+		col |= 0x4000
+	}
+	if a.rowRelative {
+		col |= 0x8000
+	}
+	return col
+}
+
+func (fc *formulaCompiler) writeRef(ixti int, a cellAddress) {
+	if ixti < 0 {
+		putVar(fc.out, uint8(ptgRefV), a.row, a.colField())
+		return
+	}
+	putVar(fc.out, uint8(ptgRef3dV), uint16(ixti), a.row, a.colField())
+}
+
+func (fc *formulaCompiler) writeArea(ixti int, first, last cellAddress) {
+	if ixti < 0 {
+		putVar(fc.out, uint8(ptgArea), first.row, last.row, first.colField(), last.colField())
+		return
+	}
+	putVar(fc.out, uint8(ptgArea3d), uint16(ixti), first.row, last.row, first.colField(), last.colField())
+}
@@ -0,0 +1,96 @@
+package xls
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCompileFormula(t *testing.T) {
+	sheetNames := []string{"worksheet", "worksheet1"}
+	tests := []struct {
+		formula string
+		rgce    string
+	}{
+		// Relative and absolute references
+		{"A1", "44" + "0000" + "00c0"},
+		{"$A$1", "44" + "0000" + "0000"},
+		{"A$1", "44" + "0000" + "0040"},
+		{"$A1", "44" + "0000" + "0080"},
+		{"C10:$D$20", "25" + "0900" + "1300" + "02c0" + "0300"},
+		{"A:B", "25" + "0000" + "ffff" + "0040" + "0140"},
+		{"2:3", "25" + "0100" + "0200" + "0080" + "ff80"},
+		// 3D references
+		{"worksheet1!B2", "5a" + "0100" + "0100" + "01c0"},
+		{"'worksheet'!$A$1:B2", "3b" + "0000" + "0000" + "0100" + "0000" + "01c0"},
+		// Operator precedence
+		{"1+2*3", "1e0100" + "1e0200" + "1e0300" + "05" + "03"},
+		{"(1+2)*3", "1e0100" + "1e0200" + "03" + "15" + "1e0300" + "05"},
+		{"1&2=3", "1e0100" + "1e0200" + "08" + "1e0300" + "0b"},
+		{"2^3^2", "1e0200" + "1e0300" + "07" + "1e0200" + "07"},
+		// Unary minus goes before the power, so -2^2 is 4
+		{"-2^2", "1e0200" + "13" + "1e0200" + "07"},
+		{"1--A1", "1e0100" + "44000000c0" + "13" + "04"},
+		// Percent
+		{"50%", "1e3200" + "14"},
+		{"A1%*2", "44000000c0" + "14" + "1e0200" + "05"},
+		// Constants
+		{"1.5", "1f" + "000000000000f83f"},
+		{`"a""b"`, "17" + "0301" + "610022006200"},
+		{"TRUE", "1d01"},
+		{"#N/A", "1c2a"},
+		// Functions with a fixed and a variable number of arguments
+		{"ROUND(A1,0)", "24000000c0" + "1e0000" + "41" + "1b00"},
+		{"SUM(A1)", "24000000c0" + "42" + "01" + "0400"},
+		{"SUM(A1:B2,3)", "25" + "0000" + "0100" + "00c0" + "01c0" + "1e0300" + "42" + "02" + "0400"},
+		{"MAX(1,2,3)", "1e0100" + "1e0200" + "1e0300" + "42" + "03" + "0700"},
+		{"IF(A1>0,1)", "44000000c0" + "1e0000" + "0d" + "1e0100" + "42" + "02" + "0100"},
+		{"IF(A1,,1)", "24000000c0" + "16" + "1e0100" + "42" + "03" + "0100"},
+		{"NOW()", "41" + "4a00"},
+	}
+
+	for _, tt := range tests {
+		rgce, err := compileFormula(tt.formula, sheetNames)
+		if err != nil {
+			t.Errorf("compileFormula(%q): %v", tt.formula, err)
+			continue
+		}
+		if got := hex.EncodeToString(rgce); got != tt.rgce {
+			t.Errorf("compileFormula(%q) = %s, want %s", tt.formula, got, tt.rgce)
+		}
+	}
+}
+
+func TestCompileFormulaStringLength(t *testing.T) {
+	// 127 surrogate pairs are 254 UTF-16 characters, the length byte of ptgStr counts them
+	rgce, err := compileFormula(`"`+strings.Repeat("😀", 127)+`"`, []string{"worksheet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rgce[0] != ptgStr || rgce[1] != 254 || len(rgce) != 3+2*254 {
+		t.Errorf("ptgStr % x, %d bytes", rgce[:3], len(rgce))
+	}
+}
+
+func TestCompileFormulaErrors(t *testing.T) {
+	tests := []string{
+		"SUM(",
+		"1+",
+		"(1",
+		"ROUND(1)",
+		"NOSUCHFUNCTION(1)",
+		"unknown!A1",
+		"A65537",
+		`"unterminated`,
+		"1 2",
+		strings.Repeat("1+", 700) + "1",
+		`"` + strings.Repeat("a", 256) + `"`,
+		`"` + strings.Repeat("😀", 128) + `"`, // 256 UTF-16 characters
+	}
+
+	for _, formula := range tests {
+		if _, err := compileFormula(formula, []string{"worksheet"}); err == nil {
+			t.Errorf("compileFormula(%.20q) succeeded", formula)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package xls
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxHeaderFooterLength is the longest header or footer template of a BIFF8 worksheet
+const maxHeaderFooterLength = 255
+
+// headerFooterCodes are the Excel &-codes without the section codes &L, &C and &R
+var headerFooterCodes = map[rune]string{
+	'P': "page number",
+	'N': "total number of pages",
+	'D': "date",
+	'T': "time",
+	'F': "file name",
+	'Z': "file path",
+	'A': "sheet name",
+	'B': "bold",
+	'I': "italic",
+	'U': "underline",
+	'E': "double underline",
+	'S': "strikethrough",
+	'X': "superscript",
+	'Y': "subscript",
+	'O': "outline",
+	'H': "shadow",
+}
+
+// HeaderFooter is a page header or footer with the left, center and right sections.
+// The sections can have Excel's &-codes: &P page number, &N total number of pages, &D date, &T time, &F file name, &Z file path,
+// &A sheet name, &B bold, &I italic, &U underline, &E double underline, &S strikethrough, &X superscript, &Y subscript,
+// &"font,style" font, &nn font size in points and && an ampersand, e.g. "Page &P of &N".
+type HeaderFooter struct {
+	Left   string
+	Center string
+	Right  string
+}
+
+// String returns the Excel template of the header or footer, e.g. "&LReport&CPage &P of &N&R&D"
+func (hf HeaderFooter) String() string {
+	var template strings.Builder
+	if hf.Left != "" {
+		template.WriteString("&L" + hf.Left)
+	}
+	if hf.Center != "" {
+		template.WriteString("&C" + hf.Center)
+	}
+	if hf.Right != "" {
+		template.WriteString("&R" + hf.Right)
+	}
+	return template.String()
+}
+
+// ParseHeaderFooter splits an Excel template like "&LReport&CPage &P of &N&R&D" into the sections, the text before &L, &C or &R is centered.
+// It returns ErrInvalidHeaderFooter if the template has an unknown &-code or is longer than 255 characters.
+func ParseHeaderFooter(template string) (HeaderFooter, error) {
+	hf, err := parseHeaderFooter(template)
+	if err != nil {
+		return HeaderFooter{}, err
+	}
+	if err := hf.check(); err != nil {
+		return HeaderFooter{}, err
+	}
+
+	return hf, nil
+}
+
+// parseHeaderFooter splits the template into the sections and checks the &-codes
+func parseHeaderFooter(template string) (HeaderFooter, error) {
+	var hf HeaderFooter
+	section := &hf.Center
+
+	runes := []rune(template)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '&' {
+			*section += string(runes[i])
+			continue
+		}
+
+		i++
+		if i == len(runes) {
+			return HeaderFooter{}, fmt.Errorf("%w: %q ends with &", ErrInvalidHeaderFooter, template)
+		}
+		switch r := runes[i]; {
+		case r == 'L':
+			section = &hf.Left
+		case r == 'C':
+			section = &hf.Center
+		case r == 'R':
+			section = &hf.Right
+		case r == '&':
+			*section += "&&"
+		case r == '"':
+			// The font name and style go up to the closing quote: &"Arial,Bold"
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return HeaderFooter{}, fmt.Errorf("%w: %q has an unclosed font name", ErrInvalidHeaderFooter, template)
+			}
+			*section += "&" + string(runes[i:end+1])
+			i = end
+		case r >= '0' && r <= '9':
+			// The font size has up to three digits: &12
+			end := i + 1
+			for end < len(runes) && end < i+3 && runes[end] >= '0' && runes[end] <= '9' {
+				end++
+			}
+			*section += "&" + string(runes[i:end])
+			i = end - 1
+		default:
+			if _, ok := headerFooterCodes[r]; !ok {
+				return HeaderFooter{}, fmt.Errorf("%w: %q has an unknown code &%c", ErrInvalidHeaderFooter, template, r)
+			}
+			*section += "&" + string(r)
+		}
+	}
+
+	return hf, nil
+}
+
+// check returns ErrInvalidHeaderFooter if the template is longer than 255 characters or a section has an unknown &-code
+func (hf HeaderFooter) check() error {
+	template := hf.String()
+	if utf16Length(template) > maxHeaderFooterLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidHeaderFooter, template, maxHeaderFooterLength)
+	}
+
+	for _, s := range []string{hf.Left, hf.Center, hf.Right} {
+		parsed, err := parseHeaderFooter(s)
+		if err != nil {
+			return err
+		}
+		if parsed != (HeaderFooter{Center: s}) {
+			return fmt.Errorf("%w: %q has a section code in a section", ErrInvalidHeaderFooter, s)
+		}
+	}
+
+	return nil
+}
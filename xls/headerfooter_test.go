@@ -0,0 +1,38 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFooterLength(t *testing.T) {
+	// "&C" and 126 surrogate pairs are 254 UTF-16 characters, one more pair makes 256
+	if err := (HeaderFooter{Center: strings.Repeat("😀", 126)}).check(); err != nil {
+		t.Errorf("check() of 254 UTF-16 characters = %v", err)
+	}
+	if err := (HeaderFooter{Center: strings.Repeat("😀", 127)}).check(); !errors.Is(err, ErrInvalidHeaderFooter) {
+		t.Errorf("check() of 256 UTF-16 characters = %v, want ErrInvalidHeaderFooter", err)
+	}
+}
+
+func TestWriteHeaderNonBMP(t *testing.T) {
+	ws := &worksheet{header: HeaderFooter{Center: "😀"}, footer: HeaderFooter{Right: "a😀"}}
+
+	for name, write := range map[string]func(*bytes.Buffer){"HEADER": ws.writeHeader, "FOOTER": ws.writeFooter} {
+		buffer := new(bytes.Buffer)
+		write(buffer)
+		data := buffer.Bytes()
+
+		length, cch := int(binary.LittleEndian.Uint16(data[2:])), int(binary.LittleEndian.Uint16(data[4:]))
+		if length != len(data)-4 {
+			t.Errorf("%s length = %d, want %d", name, length, len(data)-4)
+		}
+		// The string length, the option flags and the UTF-16 characters
+		if 3+2*cch != length {
+			t.Errorf("%s has %d characters in %d bytes", name, cch, length)
+		}
+	}
+}
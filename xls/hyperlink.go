@@ -0,0 +1,99 @@
+package xls
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxHyperlinkLength is the longest link target Excel accepts
+const maxHyperlinkLength = 2079
+
+var hyperlinkURLRe = regexp.MustCompile(`(?i)^(https?|ftp)://[^\s]+$`)
+var hyperlinkEmailRe = regexp.MustCompile(`^[^@\s:/]+@[^@\s:/]+\.[^@\s:/]+$`)
+
+// hlinkClsid is the CLSID of the hyperlink object, {79EAC9D0-BAF9-11CE-8C82-00AA004BA90B}
+var hlinkClsid = []byte{0xD0, 0xC9, 0xEA, 0x79, 0xF9, 0xBA, 0xCE, 0x11, 0x8C, 0x82, 0x00, 0xAA, 0x00, 0x4B, 0xA9, 0x0B}
+
+// urlMonikerClsid is the CLSID of the URL moniker, {79EAC9E0-BAF9-11CE-8C82-00AA004BA90B}
+var urlMonikerClsid = []byte{0xE0, 0xC9, 0xEA, 0x79, 0xF9, 0xBA, 0xCE, 0x11, 0x8C, 0x82, 0x00, 0xAA, 0x00, 0x4B, 0xA9, 0x0B}
+
+// hyperlink is a link of a cell to a web page, an email address or a place in the workbook
+type hyperlink struct {
+	row      int
+	col      int
+	url      string // Web page or mailto: address, empty for an internal link
+	location string // Cell or range like "worksheet1!A1" of an internal link
+}
+
+// detectHyperlink returns the link target if the value is a URL, a mailto: address or an email address
+func detectHyperlink(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !fitsHyperlink(value) {
+		return "", false
+	}
+	if hyperlinkURLRe.MatchString(value) || hyperlinkEmailRe.MatchString(value) {
+		return value, true
+	}
+	if len(value) > len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value, true
+	}
+	return "", false
+}
+
+// newHyperlink returns the link of the cell to the target: "#worksheet1!A1" is an internal link,
+// an email address links to mailto:, anything else is a URL
+func newHyperlink(row, col int, target string) hyperlink {
+	target = strings.TrimSpace(target)
+	link := hyperlink{row: row, col: col}
+	switch {
+	case strings.HasPrefix(target, "#"):
+		link.location = target[1:]
+	case hyperlinkEmailRe.MatchString(target):
+		link.url = "mailto:" + target
+	default:
+		link.url = target
+	}
+	return link
+}
+
+// fitsHyperlink returns false if the link to the target is longer than the 2079 characters Excel accepts
+func fitsHyperlink(target string) bool {
+	link := newHyperlink(0, 0, target)
+	return len(utf16.Encode([]rune(link.url+link.location))) <= maxHyperlinkLength
+}
+
+// writeHyperlinkString writes a null terminated UTF-16LE string with its length in characters
+func writeHyperlinkString(buffer *bytes.Buffer, str string) {
+	chars := append(utf16.Encode([]rune(str)), 0)
+	putVar(buffer, uint32(len(chars)), chars)
+}
+
+// write writes the HLINK record of the link
+func (link hyperlink) write(buffer *bytes.Buffer) {
+	var record uint16 = 0x01B8 // Record identifier
+
+	var streamVersion uint32 = 2
+	var flags uint32
+
+	data := new(bytes.Buffer)
+	putVar(data, uint16(link.row), uint16(link.row), uint16(link.col), uint16(link.col))
+	putVar(data, hlinkClsid, streamVersion)
+
+	if link.url != "" {
+		flags = 0x0003 // Has a moniker, the link is absolute
+		putVar(data, flags, urlMonikerClsid)
+
+		// The URL moniker length is in bytes and counts the null terminator
+		chars := append(utf16.Encode([]rune(link.url)), 0)
+		putVar(data, uint32(2*len(chars)), chars)
+	} else {
+		flags = 0x0008 // Has a location in the workbook
+		putVar(data, flags)
+		writeHyperlinkString(data, link.location)
+	}
+
+	putVar(buffer, record, uint16(data.Len()))
+	buffer.Write(data.Bytes())
+}
@@ -0,0 +1,42 @@
+package xls
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectHyperlinkLength(t *testing.T) {
+	url := "https://example.com/"
+	longest := url + strings.Repeat("a", maxHyperlinkLength-len(url))
+	email := "@example.com"
+	longestEmail := strings.Repeat("a", maxHyperlinkLength-len("mailto:")-len(email)) + email
+
+	tests := []struct {
+		value  string
+		linked bool
+	}{
+		{longest, true},
+		{longest + "a", false},
+		{longestEmail, true},
+		{"a" + longestEmail, false}, // The mailto: prefix counts
+	}
+
+	for _, tt := range tests {
+		if _, linked := detectHyperlink(tt.value); linked != tt.linked {
+			t.Errorf("detectHyperlink() of %d characters = %v, want %v", len(tt.value), linked, tt.linked)
+		}
+	}
+}
+
+func TestLinkTargetLength(t *testing.T) {
+	ws := &worksheet{linkColumns: map[int]int{0: 1}}
+	cell := cellValue{kind: cellKindString, str: "Link"}
+	target := "#worksheet1!" + strings.Repeat("A", maxHyperlinkLength-len("worksheet1!"))
+
+	if _, linked := ws.linkTarget([]string{"Link", target}, 0, cell); !linked {
+		t.Errorf("linkTarget() of %d characters is not linked", len(target)-1)
+	}
+	if _, linked := ws.linkTarget([]string{"Link", target + "A"}, 0, cell); linked {
+		t.Errorf("linkTarget() of %d characters is linked", len(target))
+	}
+}
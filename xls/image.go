@@ -0,0 +1,192 @@
+package xls
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Office Drawing records of the BLIP store
+const (
+	escherBStoreContainer uint16 = 0xF001
+	escherBSE             uint16 = 0xF007
+	escherBlipJPEG        uint16 = 0xF01D
+	escherBlipPNG         uint16 = 0xF01E
+)
+
+// Pixel sizes of the cells of the Calibri 11 default font
+const (
+	columnWidthPixels  = 7  // Pixels per character of the column width
+	defaultColumnPixel = 64 // Pixels of a column without COLINFO, DEFCOLWIDTH is 8 characters with the padding
+	rowHeightPixels    = 20 // Pixels of a row of defaultRowHeight points
+)
+
+// Image is a PNG or JPEG picture placed on a worksheet, the top left corner of the picture is in the cell
+type Image struct {
+	Data   []byte // PNG or JPEG file contents
+	Row    int    // 0-based row index
+	Column int    // 0-based column index
+	Width  int    // Width in pixels, zero is the width of the picture
+	Height int    // Height in pixels, zero is the height of the picture
+}
+
+// blip is a picture of the BLIP store of the drawing group
+type blip struct {
+	blipType uint16 // MSOBLIPTYPE: 5 - JPEG, 6 - PNG
+	uid      [16]byte
+	data     []byte
+	refs     uint32 // Number of the shapes showing the picture
+}
+
+// sheetImage is an image of a worksheet with its size and the 1-based index of its picture in the BLIP store
+type sheetImage struct {
+	Image
+	blipType uint16
+	pib      uint32
+}
+
+// newSheetImage checks the image and fills in its size, it returns ErrInvalidImage if the picture is neither PNG nor JPEG
+func newSheetImage(img Image) (sheetImage, error) {
+	if img.Row < 0 || img.Row > 65535 || img.Column < 0 || img.Column > 255 {
+		return sheetImage{}, fmt.Errorf("%w: cell out of the worksheet", ErrInvalidImage)
+	}
+	if img.Width < 0 || img.Height < 0 {
+		return sheetImage{}, fmt.Errorf("%w: %s size must not be negative", ErrInvalidImage, cellName(img.Row, img.Column))
+	}
+
+	var config image.Config
+	var err error
+	si := sheetImage{Image: img}
+	switch {
+	case bytes.HasPrefix(img.Data, []byte("\x89PNG\r\n\x1a\n")):
+		si.blipType = 6
+		config, err = png.DecodeConfig(bytes.NewReader(img.Data))
+	case bytes.HasPrefix(img.Data, []byte{0xFF, 0xD8}):
+		si.blipType = 5
+		config, err = jpeg.DecodeConfig(bytes.NewReader(img.Data))
+	default:
+		return sheetImage{}, fmt.Errorf("%w: %s is neither PNG nor JPEG", ErrInvalidImage, cellName(img.Row, img.Column))
+	}
+	if err != nil {
+		return sheetImage{}, fmt.Errorf("%w: %s: %v", ErrInvalidImage, cellName(img.Row, img.Column), err)
+	}
+
+	if si.Width == 0 {
+		si.Width = config.Width
+	}
+	if si.Height == 0 {
+		si.Height = config.Height
+	}
+
+	return si, nil
+}
+
+// addBlip adds the picture to the BLIP store, it returns the 1-based index of the picture.
+// The same picture shown on several worksheets is stored once.
+func (dg *drawingGroup) addBlip(blipType uint16, data []byte) uint32 {
+	uid := md5.Sum(data)
+	for i := range dg.blips {
+		if dg.blips[i].uid == uid {
+			dg.blips[i].refs++
+			return uint32(i + 1)
+		}
+	}
+
+	dg.blips = append(dg.blips, blip{blipType, uid, data, 1})
+	return uint32(len(dg.blips))
+}
+
+// writeBStore writes the BStoreContainer with a BSE record of every picture
+func (dg *drawingGroup) writeBStore(buffer *bytes.Buffer) {
+	if len(dg.blips) == 0 {
+		return
+	}
+
+	data := new(bytes.Buffer)
+	for _, b := range dg.blips {
+		recordType, instance := escherBlipPNG, uint16(0x06E0)
+		if b.blipType == 5 {
+			recordType, instance = escherBlipJPEG, uint16(0x046A)
+		}
+		blipData := new(bytes.Buffer)
+		putVar(blipData, b.uid[:], uint8(0xFF), b.data)
+		blipRecord := new(bytes.Buffer)
+		escherRecord(blipRecord, 0, instance, recordType, blipData.Bytes())
+
+		bse := new(bytes.Buffer)
+		putVar(bse, uint8(b.blipType), uint8(b.blipType), b.uid[:], uint16(0x00FF))
+		putVar(bse, uint32(blipRecord.Len()), b.refs, uint32(0), uint8(0), uint8(0), uint8(0), uint8(0))
+		bse.Write(blipRecord.Bytes())
+		escherRecord(data, 0x2, b.blipType, escherBSE, bse.Bytes())
+	}
+
+	escherRecord(buffer, escherContainerVersion, uint16(len(dg.blips)), escherBStoreContainer, data.Bytes())
+}
+
+// objPicture writes the OBJ record of a picture
+func objPicture(buffer *bytes.Buffer, objID uint16) {
+	var record uint16 = 0x005D // Record identifier
+	var length uint16 = 0x0026 // Bytes to follow
+
+	putVar(buffer, record, length)
+
+	// ftCmo: picture
+	putVar(buffer, uint16(0x0015), uint16(0x0012), uint16(0x0008), objID, uint16(0x6011), uint32(0), uint32(0), uint32(0))
+	// ftCf: clipboard format of the picture
+	putVar(buffer, uint16(0x0007), uint16(0x0002), uint16(0xFFFF))
+	// ftPioGrbit: picture options
+	putVar(buffer, uint16(0x0008), uint16(0x0002), uint16(0x0001))
+	// ftEnd
+	putVar(buffer, uint16(0x0000), uint16(0x0000))
+}
+
+// shape returns the picture frame shape of the image, the anchor is set when the column widths are known
+func (si sheetImage) shape() drawingShape {
+	return drawingShape{
+		shapeType: 75, // Picture frame
+		flags:     0x0A00,
+		properties: []escherProperty{
+			{0x007F, 0x00800080}, // Lock the aspect ratio
+			{0x4104, si.pib},     // Picture of the BLIP store
+			{0x01BF, 0x00100000}, // No fill hit test
+		},
+		anchorFlag: 2,
+		obj:        objPicture,
+	}
+}
+
+// columnPixels returns the width of the column in pixels
+func (ws *worksheet) columnPixels(columnIdx int) int {
+	if columnIdx > ws.maxColumn() {
+		return defaultColumnPixel
+	}
+	return ws.columnWidth(columnIdx) * columnWidthPixels
+}
+
+// imageAnchor returns the cells covered by the image, the offsets are in 1/1024 of the column width and in 1/256 of the row height
+func (ws *worksheet) imageAnchor(si sheetImage) clientAnchor {
+	col, x := si.Column, si.Width
+	for col < 255 && x >= ws.columnPixels(col) {
+		x -= ws.columnPixels(col)
+		col++
+	}
+	if width := ws.columnPixels(col); x > width {
+		x = width
+	}
+
+	row, y := si.Row+(si.Height/rowHeightPixels), si.Height%rowHeightPixels
+	if row > 65535 {
+		row, y = 65535, rowHeightPixels
+	}
+
+	dx := 0
+	if width := ws.columnPixels(col); width > 0 {
+		dx = x * 1024 / width
+	}
+	dy := y * 256 / rowHeightPixels
+
+	return clientAnchor{uint16(si.Column), 0, uint16(si.Row), 0, uint16(col), uint16(dx), uint16(row), uint16(dy)}
+}
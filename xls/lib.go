@@ -0,0 +1,218 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+// padRight ...
+func padRight(str, pad string, lenght int) string {
+	for {
+		str += pad
+		if len(str) > lenght {
+			return str[0:lenght]
+		}
+	}
+}
+
+// putVar ...
+func putVar(w io.Writer, args ...interface{}) {
+	for _, i := range args {
+		err := binary.Write(w, binary.LittleEndian, i)
+		if err != nil {
+			fmt.Println(err)
+			panic("Cannot write int16")
+		}
+	}
+}
+
+// localDateToOLE ...
+func localDateToOLE(timestamp int64) string {
+	var factor int64 = 4294967296
+	var days int64 = 134774
+	bigDate := days*24*3600 + timestamp + 10800
+	bigDate *= 10000000
+
+	highPart := int64(float64(bigDate) / float64(factor))
+	// lower 4 bytes
+	lowPart := int(math.Floor(((float64(bigDate) / float64(factor)) - float64(highPart)) * float64(factor)))
+
+	buf := new(bytes.Buffer)
+	var hex int
+	for i := 0; i < 4; i++ {
+		hex = lowPart % 256
+		putVar(buf, uint8(hex))
+		lowPart = int(math.Floor(float64(lowPart) / 256))
+	}
+	for i := 0; i < 4; i++ {
+		hex = int(highPart) % 256
+		putVar(buf, uint8(hex))
+		highPart = int64(math.Floor(float64(highPart) / 0x100))
+	}
+
+	return buf.String()
+}
+
+// ascToUcs utility function to transform ASCII text to Unicode.
+func ascToUcs(ascii string) string {
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(ascii); i++ {
+		putVar(buf, ascii[i], []byte("\x00"))
+	}
+
+	return buf.String()
+}
+
+// utf8toBIFF8UnicodeShort converts a UTF-8 string into BIFF8 Unicode string data (8-bit string length)
+func utf8toBIFF8UnicodeShort(value string) string {
+	buf := new(bytes.Buffer)
+	utf16str := utf16.Encode([]rune(value))
+	putVar(buf, uint8(len(utf16str)), uint8(0x0001), utf16str)
+
+	return buf.String()
+}
+
+// utf8toBIFF8UnicodeLong converts a UTF-8 string into BIFF8 Unicode string data (16-bit string length)
+func utf8toBIFF8UnicodeLong(value string) string {
+	buf := new(bytes.Buffer)
+	utf16str := utf16.Encode([]rune(value))
+	putVar(buf, uint16(len(utf16str)), uint8(0x0001), utf16str)
+
+	return buf.String()
+}
+
+// utf16Length returns the number of UTF-16 characters of value, the BIFF8 string lengths count them
+func utf16Length(value string) int {
+	return len(utf16.Encode([]rune(value)))
+}
+
+// truncateUTF16 cuts value to at most length UTF-16 characters without splitting a surrogate pair
+func truncateUTF16(value string, length int) string {
+	for i, r := range value {
+		size := 1
+		if r >= 0x10000 {
+			size = 2
+		}
+		if length -= size; length < 0 {
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// cp1252Specials are the characters of the 0x80 - 0x9F range of the Windows-1252 code page
+var cp1252Specials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// cp1252Byte converts r into its Windows-1252 byte, ok is false if the code page has no such character
+func cp1252Byte(r rune) (b byte, ok bool) {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	b, ok = cp1252Specials[r]
+	return b, ok
+}
+
+// encodeRK converts num into the RK value format, ok is false if num cannot be stored as RK without loss
+func encodeRK(num float64) (rk uint32, ok bool) {
+	// signed 30-bit integer
+	if num == math.Trunc(num) && num >= -(1<<29) && num < (1<<29) {
+		return uint32(int32(num))<<2 | 0x02, true
+	}
+
+	// signed 30-bit integer divided by 100
+	num100 := math.Round(num * 100)
+	if num100 >= -(1<<29) && num100 < (1<<29) && num100/100 == num {
+		return uint32(int32(num100))<<2 | 0x03, true
+	}
+
+	// IEEE number with the 34 least significant bits cleared
+	if bits := math.Float64bits(num); bits&0x3FFFFFFFF == 0 {
+		return uint32(bits >> 32), true
+	}
+
+	// IEEE number divided by 100
+	if bits := math.Float64bits(num * 100); bits&0x3FFFFFFFF == 0 && math.Float64frombits(bits)/100 == num {
+		return uint32(bits>>32) | 0x01, true
+	}
+
+	return 0, false
+}
+
+// excelSerialDate converts the wall clock of t into a serial date of the 1900 date system.
+// ok is false for the dates out of 1900-01-01 - 9999-12-31 which cannot be represented.
+func excelSerialDate(t time.Time) (serial float64, ok bool) {
+	wallClock := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+
+	// 25569 is the serial date of 1970-01-01
+	serial = (float64(wallClock.Unix())+float64(wallClock.Nanosecond())/1e9)/86400 + 25569
+	// Excel treats 1900 as a leap year, so the serials before 1900-03-01 are shifted by one day
+	if serial < 61 {
+		serial--
+	}
+	if serial < 1 || serial >= 2958466 {
+		return 0, false
+	}
+
+	return serial, true
+}
+
+// columnName converts a 0-based column index into the column letters
+func columnName(columnIdx int) string {
+	name := ""
+	for columnIdx++; columnIdx > 0; columnIdx = (columnIdx - 1) / 26 {
+		name = string(rune('A'+(columnIdx-1)%26)) + name
+	}
+	return name
+}
+
+// cellName converts 0-based row and column indexes into an A1 style cell reference
+func cellName(rowIdx, columnIdx int) string {
+	return columnName(columnIdx) + strconv.Itoa(rowIdx+1)
+}
+
+// max returns the larger of x or y.
+func max(x, y int) int {
+	if x < y {
+		return y
+	}
+	return x
+}
+
+// min returns the smaller of x or y.
+func min(x, y int) int {
+	if x > y {
+		return y
+	}
+	return x
+}
+
+// maxUInt16 ...
+func maxUInt16(x, y uint16) uint16 {
+	if x < y {
+		return y
+	}
+	return x
+}
+
+// minUInt16 ...
+func minUInt16(x, y uint16) uint16 {
+	if x > y {
+		return y
+	}
+	return x
+}
+
+func substr(slice []byte, start, length int) []byte {
+	return slice[start : start+length]
+}
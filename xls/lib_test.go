@@ -0,0 +1,76 @@
+package xls
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeRK(t *testing.T) {
+	tests := []struct {
+		name string
+		num  float64
+		rk   uint32
+		ok   bool
+	}{
+		{"integer", 42, 42<<2 | 0x02, true},
+		{"zero", 0, 0x02, true},
+		{"negative integer", -5, 0xFFFFFFEE, true},
+		{"largest 30-bit integer", 1<<29 - 1, (1<<29-1)<<2 | 0x02, true},
+		{"integer divided by 100", 1.5, 150<<2 | 0x03, true},
+		{"negative integer divided by 100", -0.25, 0xFFFFFF9F, true},
+		{"IEEE number", math.Ldexp(1, -20), 0x3EB00000, true},
+		{"IEEE number above 30 bits", 1 << 40, 0x42700000, true},
+		{"IEEE number divided by 100", 10995116277.76, 0x42700000 | 0x01, true},
+		{"too precise", math.Pi, 0, false},
+		{"too large with a fraction", 1234567.891, 0, false},
+	}
+
+	for _, tt := range tests {
+		rk, ok := encodeRK(tt.num)
+		if ok != tt.ok || rk != tt.rk {
+			t.Errorf("%s: encodeRK(%v) = %#08x, %v, want %#08x, %v", tt.name, tt.num, rk, ok, tt.rk, tt.ok)
+		}
+	}
+}
+
+func TestTruncateUTF16(t *testing.T) {
+	tests := []struct {
+		value  string
+		length int
+		want   string
+	}{
+		{"abc", 5, "abc"},
+		{"abcdef", 3, "abc"},
+		{"äöüß", 2, "äö"},
+		{"a😀b", 2, "a"},
+		{"a😀b", 3, "a😀"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateUTF16(tt.value, tt.length); got != tt.want {
+			t.Errorf("truncateUTF16(%q, %d) = %q, want %q", tt.value, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestUTF8toBIFF8Unicode(t *testing.T) {
+	tests := []struct {
+		value string
+		short string
+		long  string
+	}{
+		{"ab", "\x02\x01a\x00b\x00", "\x02\x00\x01a\x00b\x00"},
+		{"é", "\x01\x01\xe9\x00", "\x01\x00\x01\xe9\x00"},
+		// A character out of the Basic Multilingual Plane is a surrogate pair, two UTF-16 characters
+		{"a😀", "\x03\x01a\x00\x3d\xd8\x00\xde", "\x03\x00\x01a\x00\x3d\xd8\x00\xde"},
+	}
+
+	for _, tt := range tests {
+		if got := utf8toBIFF8UnicodeShort(tt.value); got != tt.short {
+			t.Errorf("utf8toBIFF8UnicodeShort(%q) = % x, want % x", tt.value, got, tt.short)
+		}
+		if got := utf8toBIFF8UnicodeLong(tt.value); got != tt.long {
+			t.Errorf("utf8toBIFF8UnicodeLong(%q) = % x, want % x", tt.value, got, tt.long)
+		}
+	}
+}
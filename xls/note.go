@@ -0,0 +1,153 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxNoteLength is the longest text of a cell note
+const maxNoteLength = 32767
+
+// maxTxoContinueChars is the number of UTF-16 characters fitting into a CONTINUE record of the TXO text
+const maxTxoContinueChars = 4111
+
+// Note is a note (comment) of a cell, Excel shows it when the mouse is over the cell
+type Note struct {
+	Row    int // 0-based row index
+	Column int // 0-based column index
+	Text   string
+}
+
+// ReadNotes reads a JSON object mapping the cell references like "B2" or "worksheet1!B2" to the note texts, e.g. {"B2": "Check the total"}.
+// It returns the worksheet indexes and the notes, the worksheet index is AllSheets when the reference has no worksheet name.
+func ReadNotes(r io.Reader) ([]int, []Note, error) {
+	var refs map[string]string
+	if err := json.NewDecoder(r).Decode(&refs); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidNote, err)
+	}
+
+	// The references are sorted to write the notes in the same order on every run
+	keys := make([]string, 0, len(refs))
+	for ref := range refs {
+		keys = append(keys, ref)
+	}
+	sort.Strings(keys)
+
+	sheets := make([]int, 0, len(refs))
+	notes := make([]Note, 0, len(refs))
+	for _, ref := range keys {
+		sheet, row, column, err := ParseCell(strings.TrimSpace(ref))
+		if err != nil {
+			return nil, nil, err
+		}
+		sheets = append(sheets, sheet)
+		notes = append(notes, Note{row, column, refs[ref]})
+	}
+
+	return sheets, notes, nil
+}
+
+// check returns ErrInvalidNote if the note is out of the worksheet or its text is empty or too long
+func (n Note) check() error {
+	if n.Row < 0 || n.Row > 65535 || n.Column < 0 || n.Column > 255 {
+		return fmt.Errorf("%w: cell out of the worksheet", ErrInvalidNote)
+	}
+	if n.Text == "" || len(utf16.Encode([]rune(n.Text))) > maxNoteLength {
+		return fmt.Errorf("%w: %s text must have 1 - %d characters", ErrInvalidNote, cellName(n.Row, n.Column), maxNoteLength)
+	}
+	return nil
+}
+
+// cellNote is a note of the worksheet with the id of its OBJ record
+type cellNote struct {
+	Note
+	objID uint16
+}
+
+// shape returns the text box shape of the note, the box is shown to the right of the cell
+func (n Note) shape() drawingShape {
+	row1 := n.Row
+	if row1 > 0 {
+		row1--
+	}
+	col2, row2 := min(n.Column+3, 255), min(row1+4, 65535)
+
+	return drawingShape{
+		shapeType: 202, // Text box
+		flags:     0x0A00,
+		properties: []escherProperty{
+			{0x0080, 0x00000000}, // Text id
+			{0x00BF, 0x00080008}, // Fit text to shape
+			{0x0158, 0x00000000}, // Connection sites
+			{0x0181, 0x08000050}, // Fill color: tooltip background
+			{0x0183, 0x08000050}, // Fill back color
+			{0x023F, 0x00030003}, // Shadow
+			{0x03BF, 0x000A0002}, // Hidden until the mouse is over the cell
+		},
+		anchor:     clientAnchor{uint16(n.Column + 1), 15, uint16(row1), 10, uint16(col2), 15, uint16(row2), 4},
+		anchorFlag: 3,
+		obj:        objNote,
+		textbox:    n.writeTxo,
+	}
+}
+
+// objNote writes the OBJ record of a note
+func objNote(buffer *bytes.Buffer, objID uint16) {
+	var record uint16 = 0x005D // Record identifier
+	var length uint16 = 0x0034 // Bytes to follow
+
+	putVar(buffer, record, length)
+
+	// ftCmo: comment
+	putVar(buffer, uint16(0x0015), uint16(0x0012), uint16(0x0019), objID, uint16(0x4011), uint32(0), uint32(0), uint32(0))
+	// ftNts: note
+	putVar(buffer, uint16(0x000D), uint16(0x0016), make([]byte, 16), uint16(0), uint32(0))
+	// ftEnd
+	putVar(buffer, uint16(0x0000), uint16(0x0000))
+}
+
+// writeTxo writes the TXO record of the note followed by the CONTINUE records of the text and of the formatting runs
+func (n Note) writeTxo(buffer *bytes.Buffer) {
+	var record uint16 = 0x01B6 // Record identifier
+	var length uint16 = 0x0012 // Bytes to follow
+
+	var grbit uint16 = 0x0212 // Left aligned, top aligned, text locked
+	var rot uint16 = 0x0000   // No rotation
+	chars := utf16.Encode([]rune(n.Text))
+	var cbRuns uint16 = 0x0010 // Two formatting runs
+
+	putVar(buffer, record, length)
+	putVar(buffer, grbit, rot, make([]byte, 6), uint16(len(chars)), cbRuns, uint32(0))
+
+	// The text is split into CONTINUE records, a surrogate pair is not split
+	for len(chars) > 0 {
+		size := min(len(chars), maxTxoContinueChars)
+		if size < len(chars) && utf16.IsSurrogate(rune(chars[size-1])) && chars[size-1] < 0xDC00 {
+			size--
+		}
+		putVar(buffer, uint16(0x003C), uint16(1+2*size), uint8(0x01), chars[:size])
+		chars = chars[size:]
+	}
+
+	// The formatting runs: the default font from the first character up to the end of the text
+	putVar(buffer, uint16(0x003C), cbRuns)
+	putVar(buffer, uint16(0), uint16(0), uint32(0))
+	putVar(buffer, uint16(len(utf16.Encode([]rune(n.Text)))), uint16(0), uint32(0))
+}
+
+// writeNote writes the NOTE record of the note, the author is cut to the 54 characters Excel accepts
+func (n cellNote) writeNote(buffer *bytes.Buffer, author string) {
+	var record uint16 = 0x001C // Record identifier
+
+	var grbit uint16 = 0x0000 // The note is hidden
+	stAuthor := []byte(utf8toBIFF8UnicodeLong(truncateUTF16(author, maxUserNameLength)))
+	length := uint16(8 + len(stAuthor) + 1)
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(n.Row), uint16(n.Column), grbit, n.objID, stAuthor, uint8(0))
+}
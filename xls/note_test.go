@@ -0,0 +1,80 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestReadNotesOrder(t *testing.T) {
+	json := `{"C3": "c", "A1": "a", "worksheet1!B2": "w", "B2": "b"}`
+
+	for i := 0; i < 10; i++ {
+		sheets, notes, err := ReadNotes(strings.NewReader(json))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		texts := ""
+		for _, note := range notes {
+			texts += note.Text
+		}
+		if texts != "abcw" || sheets[3] != 1 || sheets[0] != AllSheets {
+			t.Fatalf("ReadNotes() = %v %v, want the notes sorted by the references", sheets, notes)
+		}
+	}
+}
+
+func TestCollectNotesLength(t *testing.T) {
+	text := strings.Repeat("😀", maxNoteLength)
+	ws := &worksheet{Grid: [][]string{{"a", text}}, noteColumns: map[int]int{0: 1}}
+
+	notes := ws.collectNotes()
+	if len(notes) != 1 {
+		t.Fatalf("collectNotes() = %d notes, want 1", len(notes))
+	}
+	if err := notes[0].check(); err != nil {
+		t.Errorf("check() = %v", err)
+	}
+	if length := len(utf16.Encode([]rune(notes[0].Text))); length != maxNoteLength-1 {
+		t.Errorf("note text has %d UTF-16 characters, want %d", length, maxNoteLength-1)
+	}
+}
+
+func TestCheckNoteLength(t *testing.T) {
+	if err := (Note{Text: strings.Repeat("a", maxNoteLength)}).check(); err != nil {
+		t.Errorf("check() = %v", err)
+	}
+	// A character out of the Basic Multilingual Plane takes two UTF-16 characters
+	if err := (Note{Text: strings.Repeat("😀", maxNoteLength/2+1)}).check(); err == nil {
+		t.Error("check() of a text over 32767 UTF-16 characters = nil")
+	}
+}
+
+func TestWriteNoteAuthor(t *testing.T) {
+	tests := []struct {
+		author string
+		cch    int
+	}{
+		{strings.Repeat("x", 60), maxUserNameLength},
+		{"Jürgen 😀", 9},
+		{strings.Repeat("😀", 30), maxUserNameLength}, // 27 surrogate pairs fit
+		{"x" + strings.Repeat("😀", 30), maxUserNameLength - 1},
+	}
+
+	for _, tt := range tests {
+		buffer := new(bytes.Buffer)
+		cellNote{Note: Note{Text: "a"}, objID: 1}.writeNote(buffer, tt.author)
+
+		data := buffer.Bytes()
+		if cch := int(binary.LittleEndian.Uint16(data[12:])); cch != tt.cch {
+			t.Errorf("NOTE author %q has %d characters, want %d", tt.author, cch, tt.cch)
+		}
+		// The cell, the options, the object id, the author with its length and flags, and a padding byte
+		if length := int(binary.LittleEndian.Uint16(data[2:])); length != len(data)-4 || length != 8+3+2*tt.cch+1 {
+			t.Errorf("NOTE author %q: length = %d, %d bytes follow, want %d", tt.author, length, len(data)-4, 8+3+2*tt.cch+1)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package xls
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Orientation is the page orientation of a printed worksheet
+type Orientation int
+
+const (
+	// OrientationLandscape prints the pages wider than high
+	OrientationLandscape Orientation = iota
+	// OrientationPortrait prints the pages higher than wide
+	OrientationPortrait
+)
+
+var orientationNames = map[Orientation]string{
+	OrientationLandscape: "landscape",
+	OrientationPortrait:  "portrait",
+}
+
+// String ...
+func (o Orientation) String() string {
+	if name, ok := orientationNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("Orientation(%d)", int(o))
+}
+
+// PaperSize is the paper of a printed worksheet, the values are the SETUP record codes
+type PaperSize uint16
+
+const (
+	// PaperLetter is the US Letter paper, 8.5 x 11 inches
+	PaperLetter PaperSize = 1
+	// PaperLegal is the US Legal paper, 8.5 x 14 inches
+	PaperLegal PaperSize = 5
+	// PaperA4 is the A4 paper, 210 x 297 mm
+	PaperA4 PaperSize = 9
+)
+
+var paperSizeNames = map[PaperSize]string{
+	PaperLetter: "letter",
+	PaperLegal:  "legal",
+	PaperA4:     "a4",
+}
+
+// String ...
+func (p PaperSize) String() string {
+	if name, ok := paperSizeNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("PaperSize(%d)", int(p))
+}
+
+// PageSetup is the print layout of a worksheet
+type PageSetup struct {
+	Orientation Orientation
+	PaperSize   PaperSize
+	Scale       int // Print scaling in percent, 10 - 400, not used by FitToPages
+
+	// FitToPages shrinks the worksheet to FitWidth pages wide and FitHeight pages high, zero is as many pages as needed
+	FitToPages bool
+	FitWidth   int
+	FitHeight  int
+
+	// Margins in inches
+	MarginLeft   float64
+	MarginRight  float64
+	MarginTop    float64
+	MarginBottom float64
+	HeaderMargin float64
+	FooterMargin float64
+
+	CenterHorizontally bool
+	CenterVertically   bool
+}
+
+// DefaultPageSetup returns the page setup of the worksheets without their own page setup
+func DefaultPageSetup() PageSetup {
+	return PageSetup{
+		Orientation:  OrientationPortrait,
+		PaperSize:    PaperLetter,
+		Scale:        100,
+		MarginLeft:   0.7,
+		MarginRight:  0.7,
+		MarginTop:    0.75,
+		MarginBottom: 0.75,
+		HeaderMargin: 0.3,
+		FooterMargin: 0.3,
+	}
+}
+
+// PageSetupOption changes some settings of a page setup, the other settings are kept
+type PageSetupOption func(*PageSetup)
+
+// ParsePageSetup converts a page setup like "worksheet1!orientation=portrait|paper=a4|fit=1x0" into the worksheet index and the options changing the page setup.
+// The options are orientation (landscape, portrait), paper (letter, legal, a4), scale (10 - 400), fit (pages wide x pages high, 0 is as many as needed),
+// margins (left,right,top,bottom in inches), header-margin, footer-margin and center (horizontal, vertical, both).
+// The options of a worksheet are applied on top of the options of all the worksheets and of DefaultPageSetup.
+// The worksheet index is AllSheets when the page setup has no worksheet name.
+func ParsePageSetup(spec string) (int, []PageSetupOption, error) {
+	invalid := func() (int, []PageSetupOption, error) {
+		return 0, nil, fmt.Errorf("%w: %q", ErrInvalidPageSetup, spec)
+	}
+
+	sheet, options, err := splitSheetReference(spec)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	changes := make([]PageSetupOption, 0)
+	for _, option := range strings.Split(options, "|") {
+		if strings.TrimSpace(option) == "" {
+			continue
+		}
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return invalid()
+		}
+		value := strings.TrimSpace(kv[1])
+
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "orientation":
+			found := false
+			for o, name := range orientationNames {
+				if strings.EqualFold(name, value) {
+					orientation := o
+					changes, found = append(changes, func(ps *PageSetup) { ps.Orientation = orientation }), true
+				}
+			}
+			if !found {
+				return invalid()
+			}
+		case "paper":
+			found := false
+			for p, name := range paperSizeNames {
+				if strings.EqualFold(name, value) {
+					paper := p
+					changes, found = append(changes, func(ps *PageSetup) { ps.PaperSize = paper }), true
+				}
+			}
+			if !found {
+				return invalid()
+			}
+		case "scale":
+			scale, err := strconv.Atoi(value)
+			if err != nil {
+				return invalid()
+			}
+			changes = append(changes, func(ps *PageSetup) { ps.Scale = scale })
+		case "fit":
+			pages := strings.Split(strings.ToLower(value), "x")
+			if len(pages) != 2 {
+				return invalid()
+			}
+			width, err := strconv.Atoi(strings.TrimSpace(pages[0]))
+			if err != nil {
+				return invalid()
+			}
+			height, err := strconv.Atoi(strings.TrimSpace(pages[1]))
+			if err != nil {
+				return invalid()
+			}
+			changes = append(changes, func(ps *PageSetup) { ps.FitToPages, ps.FitWidth, ps.FitHeight = true, width, height })
+		case "margins":
+			parts := strings.Split(value, ",")
+			if len(parts) != 4 {
+				return invalid()
+			}
+			var margins [4]float64
+			for i := range margins {
+				if margins[i], err = strconv.ParseFloat(strings.TrimSpace(parts[i]), 64); err != nil {
+					return invalid()
+				}
+			}
+			changes = append(changes, func(ps *PageSetup) {
+				ps.MarginLeft, ps.MarginRight, ps.MarginTop, ps.MarginBottom = margins[0], margins[1], margins[2], margins[3]
+			})
+		case "header-margin":
+			margin, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return invalid()
+			}
+			changes = append(changes, func(ps *PageSetup) { ps.HeaderMargin = margin })
+		case "footer-margin":
+			margin, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return invalid()
+			}
+			changes = append(changes, func(ps *PageSetup) { ps.FooterMargin = margin })
+		case "center":
+			switch strings.ToLower(value) {
+			case "horizontal":
+				changes = append(changes, func(ps *PageSetup) { ps.CenterHorizontally = true })
+			case "vertical":
+				changes = append(changes, func(ps *PageSetup) { ps.CenterVertically = true })
+			case "both":
+				changes = append(changes, func(ps *PageSetup) { ps.CenterHorizontally, ps.CenterVertically = true, true })
+			default:
+				return invalid()
+			}
+		default:
+			return invalid()
+		}
+	}
+
+	return sheet, changes, nil
+}
+
+// check returns ErrInvalidPageSetup if a setting is out of the range Excel accepts
+func (ps PageSetup) check() error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s", ErrInvalidPageSetup, reason)
+	}
+
+	if _, ok := orientationNames[ps.Orientation]; !ok {
+		return invalid("unknown orientation")
+	}
+	if _, ok := paperSizeNames[ps.PaperSize]; !ok {
+		return invalid("unknown paper size")
+	}
+	if !ps.FitToPages && (ps.Scale < 10 || ps.Scale > 400) {
+		return invalid("scale must be between 10 and 400")
+	}
+	if ps.FitToPages && (ps.FitWidth < 0 || ps.FitWidth > 32767 || ps.FitHeight < 0 || ps.FitHeight > 32767) {
+		return invalid("fit to pages must be between 0 and 32767")
+	}
+	for _, m := range []float64{ps.MarginLeft, ps.MarginRight, ps.MarginTop, ps.MarginBottom, ps.HeaderMargin, ps.FooterMargin} {
+		if m < 0 || m >= 49 || math.IsNaN(m) {
+			return invalid("margins must be between 0 and 49 inches")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package xls
+
+import "bytes"
+
+// lineRange is a range of whole rows or whole columns with 0-based indexes, the last one is included
+type lineRange struct {
+	first int
+	last  int
+}
+
+// isValid ...
+func (r lineRange) isValid(max int) bool {
+	return r.first >= 0 && r.first <= r.last && r.last <= max
+}
+
+// printTitleRows returns the rows repeated at the top of every printed page, the header rows by default
+func (ws *worksheet) printTitleRows() (lineRange, bool) {
+	if ws.titleRows != nil {
+		return *ws.titleRows, true
+	}
+	if ws.HeaderRows > 0 {
+		return lineRange{0, ws.HeaderRows - 1}, true
+	}
+	return lineRange{}, false
+}
+
+// printTitles returns the parsed formula of the Print_Titles name of the worksheet with 0-based index sheet, nil without print titles
+func (ws *worksheet) printTitles(sheet int) []byte {
+	areas := make([][]byte, 0, 2)
+	if ws.titleColumns != nil {
+		areas = append(areas, area3d(sheet, CellRange{0, ws.titleColumns.first, 65535, ws.titleColumns.last}))
+	}
+	if rows, ok := ws.printTitleRows(); ok {
+		areas = append(areas, area3d(sheet, CellRange{rows.first, 0, rows.last, 255}))
+	}
+
+	switch len(areas) {
+	case 0:
+		return nil
+	case 1:
+		return areas[0]
+	}
+
+	// The columns and the rows are a union of two areas, Excel writes it as a reference expression
+	buf := new(bytes.Buffer)
+	putVar(buf, uint8(ptgMemFunc), uint16(len(areas[0])+len(areas[1])+1))
+	putVar(buf, areas[0], areas[1], uint8(ptgUnion))
+	return buf.Bytes()
+}
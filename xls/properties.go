@@ -0,0 +1,186 @@
+package xls
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Property identifiers of the DocumentSummaryInformation stream
+const (
+	pidCategory = 0x02
+	pidManager  = 0x0E
+	pidCompany  = 0x0F
+)
+
+// Variant types of the property values
+const (
+	vtI2       = 0x02
+	vtI4       = 0x03
+	vtR8       = 0x05
+	vtBool     = 0x0B
+	vtLPStr    = 0x1E
+	vtFileTime = 0x40
+)
+
+// Code pages of the property sections, the strings are 8-bit Windows-1252 or UTF-16LE
+const (
+	codePageWindows1252 = 1252
+	codePageUnicode     = 1200
+)
+
+// maxPropertyNameLength is the longest name of a custom property
+const maxPropertyNameLength = 255
+
+// CustomProperty is a user-defined document property, Excel shows it on the Custom tab of the file properties.
+// The value is a string, an int, a float64, a bool or a time.Time.
+type CustomProperty struct {
+	Name  string
+	Value interface{}
+}
+
+// propertyTypes are the value types of the "name:type=value" custom properties in the order they are inferred
+var propertyTypes = []string{"int", "float", "bool", "date", "string"}
+
+// ParseProperty converts a "name=value" or "name:type=value" custom property, the type is "int", "float", "bool", "date" or "string".
+// Without a type the value is a number if it parses as one, a bool for true and false, a date for 2006-01-02 and RFC 3339 times,
+// and a string otherwise.
+func ParseProperty(spec string) (CustomProperty, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return CustomProperty{}, fmt.Errorf("%w: %q, expected name=value or name:type=value", ErrInvalidProperty, spec)
+	}
+
+	name, value := parts[0], parts[1]
+	types := propertyTypes
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		kind := strings.ToLower(strings.TrimSpace(name[i+1:]))
+		known := false
+		for _, t := range propertyTypes {
+			known = known || t == kind
+		}
+		if !known {
+			return CustomProperty{}, fmt.Errorf("%w: %q, the type must be one of %s", ErrInvalidProperty, spec, strings.Join(propertyTypes, ", "))
+		}
+		name, types = name[:i], []string{kind}
+	}
+
+	property := CustomProperty{Name: strings.TrimSpace(name)}
+	for _, kind := range types {
+		if v, ok := parsePropertyValue(kind, value); ok {
+			property.Value = v
+			return property, property.check()
+		}
+	}
+
+	return CustomProperty{}, fmt.Errorf("%w: %q is not a valid %s value", ErrInvalidProperty, value, types[0])
+}
+
+// parsePropertyValue converts the value of the custom property type kind, ok is false if the value does not parse
+func parsePropertyValue(kind, value string) (interface{}, bool) {
+	switch kind {
+	case "int":
+		if i, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return int(i), true
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f, true
+		}
+	case "bool":
+		if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+			return strings.EqualFold(value, "true"), true
+		}
+	case "date":
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, true
+		}
+	case "string":
+		return value, true
+	}
+	return nil, false
+}
+
+// check returns ErrInvalidProperty if the name is empty or too long or the value has an unsupported type
+func (p CustomProperty) check() error {
+	if p.Name == "" || utf8.RuneCountInString(p.Name) > maxPropertyNameLength {
+		return fmt.Errorf("%w: name must have 1 - %d characters", ErrInvalidProperty, maxPropertyNameLength)
+	}
+
+	switch v := p.Value.(type) {
+	case string, bool, float64, time.Time:
+	case int:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return fmt.Errorf("%w: %s value out of the 32-bit range", ErrInvalidProperty, p.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %s value has unsupported type %T", ErrInvalidProperty, p.Name, p.Value)
+	}
+
+	return nil
+}
+
+// dataSection returns the property of the custom section with the identifier id
+func (p CustomProperty) dataSection(id uint32) dataSectionItem {
+	buf := new(bytes.Buffer)
+	switch v := p.Value.(type) {
+	case string:
+		return dataSectionItem{id, 0, vtLPStr, 0, v, uint32(len(v))}
+	case bool:
+		var value uint32 // VARIANT_BOOL: 0xFFFF is true
+		if v {
+			value = 0xFFFF
+		}
+		return dataSectionItem{id, 0, vtBool, value, "", 0}
+	case int:
+		return dataSectionItem{id, 0, vtI4, uint32(int32(v)), "", 0}
+	case float64:
+		putVar(buf, v)
+		return dataSectionItem{id, 0, vtR8, 0, buf.String(), 0}
+	case time.Time:
+		putVar(buf, fileTime(v))
+		return dataSectionItem{id, 0, vtFileTime, 0, buf.String(), 0}
+	}
+
+	return dataSectionItem{}
+}
+
+// fileTime returns the number of 100-nanosecond intervals since January 1, 1601 UTC
+func fileTime(t time.Time) uint64 {
+	const epochDiff = 116444736000000000 // From 1601-01-01 to 1970-01-01
+	return uint64(t.Unix()*10000000+int64(t.Nanosecond()/100)) + epochDiff
+}
+
+// propertyString returns the null terminated string in the code page of the section
+func propertyString(value string, codePage uint16) []byte {
+	if codePage != codePageUnicode {
+		return append([]byte(value), 0)
+	}
+	buf := new(bytes.Buffer)
+	putVar(buf, utf16.Encode([]rune(value)), uint16(0))
+	return buf.Bytes()
+}
+
+// propertyDictionary returns the UTF-16 dictionary of the custom property names, the identifiers go from 2 in the order of the properties
+func propertyDictionary(properties []CustomProperty) dataSectionItem {
+	buf := new(bytes.Buffer)
+	putVar(buf, uint32(len(properties)))
+	for i, p := range properties {
+		// The length counts the characters with the null terminator, every entry is padded to 4 bytes
+		name := propertyString(p.Name, codePageUnicode)
+		putVar(buf, uint32(i+2), uint32(len(name)/2), name)
+		if pad := buf.Len() % 4; pad != 0 {
+			buf.Write(make([]byte, 4-pad))
+		}
+	}
+
+	return dataSectionItem{0x00, 0, 0, 0, buf.String(), uint32(buf.Len())}
+}
@@ -0,0 +1,100 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParseProperty(t *testing.T) {
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec  string
+		name  string
+		value interface{}
+	}{
+		{"Batch=42", "Batch", 42},
+		{"Ratio=0.5", "Ratio", 0.5},
+		{"Reviewed=TRUE", "Reviewed", true},
+		{"Due=2024-01-31", "Due", date},
+		{"Note=a=b", "Note", "a=b"},
+		{"Batch:string=42", "Batch", "42"},
+		{"Batch:String=007", "Batch", "007"},
+		{"Ratio:float=2", "Ratio", 2.0},
+		{"Flag:string=true", "Flag", "true"},
+		{"Due:date=2024-01-31", "Due", date},
+		{"Count:int=-5", "Count", -5},
+	}
+
+	for _, tt := range tests {
+		p, err := ParseProperty(tt.spec)
+		if err != nil {
+			t.Errorf("ParseProperty(%q) error %v", tt.spec, err)
+			continue
+		}
+		if p.Name != tt.name || p.Value != tt.value {
+			t.Errorf("ParseProperty(%q) = %q %#v, want %q %#v", tt.spec, p.Name, p.Value, tt.name, tt.value)
+		}
+	}
+}
+
+func TestParsePropertyErrors(t *testing.T) {
+	for _, spec := range []string{"Batch", "=1", "Batch:integer=1", "Batch:int=1.5", "Batch:int=3000000000", "Flag:bool=yes", "Due:date=31.01.2024", "Ratio:float=NaN"} {
+		if _, err := ParseProperty(spec); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("ParseProperty(%q) = %v, want ErrInvalidProperty", spec, err)
+		}
+	}
+}
+
+func TestPropertyDictionary(t *testing.T) {
+	item := propertyDictionary([]CustomProperty{{"Straße", "x"}, {"ab", 1}})
+	data := []byte(item.dataString)
+
+	if len(data)%4 != 0 || int(item.dataLength) != len(data) {
+		t.Fatalf("dictionary length %d, data %d bytes", item.dataLength, len(data))
+	}
+	if count := binary.LittleEndian.Uint32(data); count != 2 {
+		t.Fatalf("dictionary has %d entries, want 2", count)
+	}
+
+	pos := 4
+	for i, name := range []string{"Straße", "ab"} {
+		id, cch := binary.LittleEndian.Uint32(data[pos:]), int(binary.LittleEndian.Uint32(data[pos+4:]))
+		chars := make([]uint16, cch)
+		if err := binary.Read(bytes.NewReader(data[pos+8:pos+8+2*cch]), binary.LittleEndian, chars); err != nil {
+			t.Fatal(err)
+		}
+		if id != uint32(i+2) || string(utf16.Decode(chars)) != name+"\x00" {
+			t.Errorf("dictionary entry %d = %d %q, want %d %q", i, id, string(utf16.Decode(chars)), i+2, name)
+		}
+		pos += (8 + 2*cch + 3) / 4 * 4
+	}
+}
+
+func TestWritePropertySectionStrings(t *testing.T) {
+	tests := []struct {
+		codePage uint16
+		value    string
+		want     []byte
+	}{
+		{codePageWindows1252, "abc", []byte{'a', 'b', 'c', 0}},
+		{codePageUnicode, "äb", []byte{0xE4, 0, 'b', 0, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		buffer := new(bytes.Buffer)
+		writePropertySection(buffer, tt.codePage, []dataSectionItem{{pidCompany, 0, vtLPStr, 0, tt.value, uint32(len(tt.value))}})
+		data := buffer.Bytes()
+
+		// Section size, property count, identifier and offset, then the type and the size of the string
+		if size := int(binary.LittleEndian.Uint32(data[20:])); size != len(tt.want) || !bytes.Equal(data[24:], tt.want) {
+			t.Errorf("code page %d: string %d % x, want % x", tt.codePage, size, data[24:], tt.want)
+		}
+		if size := int(binary.LittleEndian.Uint32(data)); size != len(data) {
+			t.Errorf("code page %d: section size %d, want %d", tt.codePage, size, len(data))
+		}
+	}
+}
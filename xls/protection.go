@@ -0,0 +1,231 @@
+package xls
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPasswordLength is the longest password of the legacy Excel password hash
+const maxPasswordLength = 15
+
+// maxUserNameLength is the longest name of the user who reserved the workbook
+const maxUserNameLength = 54
+
+// maxRangeTitleLength is the longest title of an allow-edit range
+const maxRangeTitleLength = 255
+
+// SheetPermission is a set of actions allowed on a protected worksheet
+type SheetPermission uint16
+
+const (
+	// AllowEditObjects allows editing the drawing objects
+	AllowEditObjects SheetPermission = 1 << iota
+	// AllowEditScenarios allows editing the scenarios
+	AllowEditScenarios
+	// AllowFormatCells allows formatting the cells
+	AllowFormatCells
+	// AllowFormatColumns allows formatting the columns
+	AllowFormatColumns
+	// AllowFormatRows allows formatting the rows
+	AllowFormatRows
+	// AllowInsertColumns allows inserting the columns
+	AllowInsertColumns
+	// AllowInsertRows allows inserting the rows
+	AllowInsertRows
+	// AllowInsertHyperlinks allows inserting the hyperlinks
+	AllowInsertHyperlinks
+	// AllowDeleteColumns allows deleting the columns
+	AllowDeleteColumns
+	// AllowDeleteRows allows deleting the rows
+	AllowDeleteRows
+	// AllowSelectLockedCells allows selecting the locked cells
+	AllowSelectLockedCells
+	// AllowSort allows sorting
+	AllowSort
+	// AllowAutoFilter allows using the autofilter
+	AllowAutoFilter
+	// AllowPivotTables allows using the pivot tables
+	AllowPivotTables
+	// AllowSelectUnlockedCells allows selecting the unlocked cells
+	AllowSelectUnlockedCells
+)
+
+// DefaultSheetPermissions are the actions Excel allows on a protected worksheet by default
+const DefaultSheetPermissions = AllowSelectLockedCells | AllowSelectUnlockedCells
+
+var sheetPermissionNames = map[SheetPermission]string{
+	AllowEditObjects:         "objects",
+	AllowEditScenarios:       "scenarios",
+	AllowFormatCells:         "format-cells",
+	AllowFormatColumns:       "format-columns",
+	AllowFormatRows:          "format-rows",
+	AllowInsertColumns:       "insert-columns",
+	AllowInsertRows:          "insert-rows",
+	AllowInsertHyperlinks:    "insert-hyperlinks",
+	AllowDeleteColumns:       "delete-columns",
+	AllowDeleteRows:          "delete-rows",
+	AllowSelectLockedCells:   "select-locked-cells",
+	AllowSort:                "sort",
+	AllowAutoFilter:          "autofilter",
+	AllowPivotTables:         "pivot-tables",
+	AllowSelectUnlockedCells: "select-unlocked-cells",
+}
+
+// String returns the comma separated names of the permissions
+func (p SheetPermission) String() string {
+	names := make([]string, 0)
+	for bit := AllowEditObjects; bit <= AllowSelectUnlockedCells; bit <<= 1 {
+		if p&bit != 0 {
+			names = append(names, sheetPermissionNames[bit])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseSheetPermissions converts comma separated permission names such as "sort,autofilter" into a SheetPermission
+func ParseSheetPermissions(names string) (SheetPermission, error) {
+	var permissions SheetPermission
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for p, n := range sheetPermissionNames {
+			if strings.EqualFold(n, name) {
+				permissions |= p
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidSheetPermission, name)
+		}
+	}
+
+	return permissions, nil
+}
+
+// sheetProtection is the protection of a worksheet
+type sheetProtection struct {
+	password    string
+	permissions SheetPermission
+}
+
+// AllowEditRange is a range of a protected worksheet that stays editable, optionally with its own password
+type AllowEditRange struct {
+	Title    string // Unique name of the range, "Range1", "Range2", ... when empty
+	Range    CellRange
+	Password string
+}
+
+// ParseAllowEditRange converts a range like "B2:C100|title=Inputs|password=secret" into the worksheet index and the allow-edit range.
+// The worksheet index is AllSheets when the range has no worksheet name.
+func ParseAllowEditRange(spec string) (int, AllowEditRange, error) {
+	options := strings.Split(spec, "|")
+	sheet, r, err := ParseRange(strings.TrimSpace(options[0]))
+	if err != nil {
+		return 0, AllowEditRange{}, err
+	}
+
+	aer := AllowEditRange{Range: r}
+	for _, option := range options[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return 0, AllowEditRange{}, fmt.Errorf("%w: %q", ErrInvalidRange, spec)
+		}
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "title":
+			aer.Title = kv[1]
+		case "password":
+			aer.Password = kv[1]
+		default:
+			return 0, AllowEditRange{}, fmt.Errorf("%w: %q", ErrInvalidRange, spec)
+		}
+	}
+
+	return sheet, aer, nil
+}
+
+// allowEditRangeTitle returns the title of the allow-edit range with 0-based index i of a worksheet
+func allowEditRangeTitle(i int, aer AllowEditRange) string {
+	if aer.Title == "" {
+		return "Range" + strconv.Itoa(i+1)
+	}
+	return aer.Title
+}
+
+// checkAllowEditRanges returns ErrInvalidRange if a title of the allow-edit ranges of a worksheet is too long
+// or is not unique, the generated "RangeN" titles included
+func checkAllowEditRanges(ranges []AllowEditRange) error {
+	titles := make(map[string]bool, len(ranges))
+	for i, aer := range ranges {
+		title := allowEditRangeTitle(i, aer)
+		if utf16Length(title) > maxRangeTitleLength {
+			return fmt.Errorf("%w: title %q is longer than %d characters", ErrInvalidRange, title, maxRangeTitleLength)
+		}
+		if titles[strings.ToLower(title)] {
+			return fmt.Errorf("%w: title %q is not unique", ErrInvalidRange, title)
+		}
+		titles[strings.ToLower(title)] = true
+	}
+	return nil
+}
+
+// cellProtection is the XF protection of the cells of a range
+type cellProtection struct {
+	r        CellRange
+	unlocked bool
+	hidden   bool
+}
+
+// passwordHash returns the legacy Excel password hash, zero for the empty password
+func passwordHash(password string) uint16 {
+	if password == "" {
+		return 0
+	}
+
+	// The password is hashed as Windows-1252 characters, the length goes first, checkPassword rejects the other characters
+	chars := []byte{byte(utf8.RuneCountInString(password))}
+	for _, r := range password {
+		b, _ := cp1252Byte(r)
+		chars = append(chars, b)
+	}
+
+	var verifier uint16
+	for i := len(chars) - 1; i >= 0; i-- {
+		verifier = ((verifier >> 14) & 0x01) | ((verifier << 1) & 0x7FFF)
+		verifier ^= uint16(chars[i])
+	}
+
+	return verifier ^ 0xCE4B
+}
+
+// checkPassword returns ErrInvalidPassword if the password is too long for the legacy password hash
+// or has a character out of the Windows-1252 code page
+func checkPassword(password string) error {
+	if utf8.RuneCountInString(password) > maxPasswordLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidPassword, maxPasswordLength)
+	}
+	for _, r := range password {
+		if _, ok := cp1252Byte(r); !ok || r == 0 {
+			return fmt.Errorf("%w: character %q is not in the Windows-1252 code page", ErrInvalidPassword, r)
+		}
+	}
+	return nil
+}
+
+// workbookProtection is the protection of the workbook structure and windows
+type workbookProtection struct {
+	password string
+	windows  bool // The workbook windows cannot be moved, resized or closed
+}
+
+// fileSharing is the write reservation of the workbook
+type fileSharing struct {
+	readOnlyRecommended bool
+	modifyPassword      string // Password to modify, the workbook opens read-only without it
+	userName            string // Name of the user who reserved the workbook
+}
@@ -0,0 +1,137 @@
+package xls
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestPasswordHash(t *testing.T) {
+	tests := []struct {
+		password string
+		hash     uint16
+	}{
+		{"", 0x0000},
+		{"abcdefghij", 0xFEF1},
+		{"password", 0x83AF},
+		{"pässwörd€", 0xA7F8}, // Hashed as the Windows-1252 bytes
+	}
+
+	for _, tt := range tests {
+		if hash := passwordHash(tt.password); hash != tt.hash {
+			t.Errorf("passwordHash(%q) = %#04x, want %#04x", tt.password, hash, tt.hash)
+		}
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	tests := []struct {
+		password string
+		valid    bool
+	}{
+		{"secret", true},
+		{"pässwörd€", true},
+		{"0123456789abcdef", false},
+		{"Ā", false},
+		{"пароль", false},
+		{"\x00", false},
+	}
+
+	for _, tt := range tests {
+		err := checkPassword(tt.password)
+		if tt.valid && err != nil || !tt.valid && !errors.Is(err, ErrInvalidPassword) {
+			t.Errorf("checkPassword(%q) = %v", tt.password, err)
+		}
+	}
+}
+
+func TestCheckAllowEditRanges(t *testing.T) {
+	long := AllowEditRange{Title: strings.Repeat("x", maxRangeTitleLength+1)}
+	tests := []struct {
+		name   string
+		ranges []AllowEditRange
+		valid  bool
+	}{
+		{"generated", []AllowEditRange{{}, {}, {Title: "Inputs"}}, true},
+		{"max length", []AllowEditRange{{Title: strings.Repeat("x", maxRangeTitleLength)}}, true},
+		{"too long", []AllowEditRange{long}, false},
+		{"duplicate", []AllowEditRange{{Title: "Inputs"}, {Title: "inputs"}}, false},
+		{"generated collision", []AllowEditRange{{Title: "Range2"}, {}}, false},
+		{"max length with pairs", []AllowEditRange{{Title: "a" + strings.Repeat("😀", 127)}}, true},
+		{"too long with pairs", []AllowEditRange{{Title: strings.Repeat("😀", 128)}}, false},
+	}
+
+	for _, tt := range tests {
+		err := checkAllowEditRanges(tt.ranges)
+		if tt.valid && err != nil || !tt.valid && !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("%s: checkAllowEditRanges() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestConvertAllowEditRangeTitles(t *testing.T) {
+	converter, err := NewCsv2XlsStreamConverter(";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter.
+		WithSheetProtection(AllSheets, "", DefaultSheetPermissions).
+		WithAllowEditRange(AllSheets, AllowEditRange{Title: "Inputs", Range: CellRange{LastRow: 1, LastColumn: 1}}).
+		WithAllowEditRange(0, AllowEditRange{Title: "INPUTS", Range: CellRange{LastRow: 1, LastColumn: 1}})
+
+	err = converter.ConvertStream(context.Background(), strings.NewReader("a;b\n"), ioutil.Discard)
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("ConvertStream() = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestFileSharingUserName(t *testing.T) {
+	tests := []struct {
+		creator string
+		cch     int
+	}{
+		{strings.Repeat("x", 60), maxUserNameLength},
+		{strings.Repeat("😀", 30), maxUserNameLength}, // 27 surrogate pairs fit
+		{"x" + strings.Repeat("😀", 30), maxUserNameLength - 1},
+	}
+
+	for _, tt := range tests {
+		converter, err := NewCsv2XlsStreamConverter(";")
+		if err != nil {
+			t.Fatal(err)
+		}
+		converter.WithCreator(tt.creator).WithReadOnlyRecommended(true)
+
+		wb := &workbook{fileSharing: converter.getFileSharing()}
+		buffer := new(bytes.Buffer)
+		wb.writeFileSharing(buffer)
+
+		data := buffer.Bytes()
+		if length := int(binary.LittleEndian.Uint16(data[2:])); length != len(data)-4 {
+			t.Errorf("%q: FILESHARING length = %d, %d bytes follow", tt.creator, length, len(data)-4)
+		}
+		// The read-only flag, the password hash, then the user name length, the option flags and the characters
+		if cch := int(binary.LittleEndian.Uint16(data[8:])); cch != tt.cch || len(data) != 11+2*cch {
+			t.Errorf("%q: user name has %d characters in %d bytes, want %d", tt.creator, cch, len(data)-11, tt.cch)
+		}
+	}
+}
+
+func TestWriteRangeProtectionTitle(t *testing.T) {
+	ws := &worksheet{editRanges: []AllowEditRange{{Title: "a😀"}}}
+	buffer := new(bytes.Buffer)
+	ws.writeRangeProtection(buffer)
+
+	data := buffer.Bytes()
+	if length := int(binary.LittleEndian.Uint16(data[2:])); length != len(data)-4 {
+		t.Fatalf("FEAT length = %d, %d bytes follow", length, len(data)-4)
+	}
+	// The title is the last field: the UTF-16 length, the option flags and the characters
+	if cch := int(binary.LittleEndian.Uint16(data[len(data)-9:])); cch != 3 {
+		t.Errorf("title has %d characters, want 3", cch)
+	}
+}
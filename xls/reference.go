@@ -0,0 +1,203 @@
+package xls
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AllSheets is the worksheet index that applies a worksheet setting to every worksheet
+const AllSheets = -1
+
+// worksheetNamePrefix is the name of the first worksheet, the next ones are numbered: worksheet1, worksheet2, ...
+const worksheetNamePrefix = "worksheet"
+
+var cellReferenceRe = regexp.MustCompile(`^\$?([A-Za-z]{1,3})\$?([0-9]{1,5})$`)
+var rowRangeRe = regexp.MustCompile(`^\$?([0-9]{1,5})(?::\$?([0-9]{1,5}))?$`)
+var columnRangeRe = regexp.MustCompile(`^\$?([A-Za-z]{1,3})(?::\$?([A-Za-z]{1,3}))?$`)
+
+// CellRange is a rectangle of cells with 0-based indexes, the last row and column are included
+type CellRange struct {
+	FirstRow    int
+	FirstColumn int
+	LastRow     int
+	LastColumn  int
+}
+
+// isValid ...
+func (r CellRange) isValid() bool {
+	return r.FirstRow >= 0 && r.FirstRow <= r.LastRow && r.LastRow <= 65535 &&
+		r.FirstColumn >= 0 && r.FirstColumn <= r.LastColumn && r.LastColumn <= 255
+}
+
+// overlaps ...
+func (r CellRange) overlaps(other CellRange) bool {
+	return r.FirstRow <= other.LastRow && other.FirstRow <= r.LastRow &&
+		r.FirstColumn <= other.LastColumn && other.FirstColumn <= r.LastColumn
+}
+
+// String returns the A1 style reference of the range
+func (r CellRange) String() string {
+	return cellName(r.FirstRow, r.FirstColumn) + ":" + cellName(r.LastRow, r.LastColumn)
+}
+
+// worksheetName returns the name of the worksheet with 0-based index idx
+func worksheetName(idx int) string {
+	if idx == 0 {
+		return worksheetNamePrefix
+	}
+	return worksheetNamePrefix + strconv.Itoa(idx)
+}
+
+// ParseSheet converts a worksheet name such as "worksheet" or "worksheet2" into a 0-based worksheet index
+func ParseSheet(name string) (int, error) {
+	name = strings.Trim(name, "'")
+	if strings.EqualFold(name, worksheetNamePrefix) {
+		return 0, nil
+	}
+	if len(name) > len(worksheetNamePrefix) && strings.EqualFold(name[:len(worksheetNamePrefix)], worksheetNamePrefix) {
+		if n, err := strconv.Atoi(name[len(worksheetNamePrefix):]); err == nil && n > 0 && n < 255 {
+			return n, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrInvalidSheet, name)
+}
+
+// splitSheetReference splits "worksheet1!A1" into the worksheet index and "A1", the index is AllSheets when there is no worksheet name
+func splitSheetReference(ref string) (int, string, error) {
+	idx := strings.LastIndex(ref, "!")
+	if idx < 0 {
+		return AllSheets, ref, nil
+	}
+
+	sheet, err := ParseSheet(ref[:idx])
+	if err != nil {
+		return 0, "", err
+	}
+
+	return sheet, ref[idx+1:], nil
+}
+
+// parseCell converts a cell reference such as "B2" into the 0-based row and column indexes
+func parseCell(ref string) (int, int, error) {
+	m := cellReferenceRe.FindStringSubmatch(ref)
+	if m == nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCell, ref)
+	}
+
+	column, err := ParseColumn(m[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCell, ref)
+	}
+	row, err := strconv.Atoi(m[2])
+	if err != nil || row < 1 || row > 65536 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCell, ref)
+	}
+
+	return row - 1, column, nil
+}
+
+// ParseCell converts a cell reference such as "B2" or "worksheet1!B2" into the worksheet index and the 0-based row and column indexes.
+// The worksheet index is AllSheets when the reference has no worksheet name.
+func ParseCell(ref string) (sheet, row, column int, err error) {
+	sheet, cell, err := splitSheetReference(ref)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	row, column, err = parseCell(cell)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return sheet, row, column, nil
+}
+
+// ParseRange converts a range reference such as "A1:D1" or "worksheet1!A1:D1" into the worksheet index and the cell range.
+// A single cell like "B2" is a range of one cell. The worksheet index is AllSheets when the reference has no worksheet name.
+func ParseRange(ref string) (sheet int, r CellRange, err error) {
+	sheet, cells, err := splitSheetReference(ref)
+	if err != nil {
+		return 0, CellRange{}, err
+	}
+
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 {
+		return 0, CellRange{}, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if r.FirstRow, r.FirstColumn, err = parseCell(parts[0]); err != nil {
+		return 0, CellRange{}, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	r.LastRow, r.LastColumn = r.FirstRow, r.FirstColumn
+	if len(parts) == 2 {
+		if r.LastRow, r.LastColumn, err = parseCell(parts[1]); err != nil {
+			return 0, CellRange{}, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+		}
+	}
+
+	// "D4:A1" is the same range as "A1:D4"
+	if r.FirstRow > r.LastRow {
+		r.FirstRow, r.LastRow = r.LastRow, r.FirstRow
+	}
+	if r.FirstColumn > r.LastColumn {
+		r.FirstColumn, r.LastColumn = r.LastColumn, r.FirstColumn
+	}
+
+	return sheet, r, nil
+}
+
+// ParseRowRange converts a row range such as "1:2" or "worksheet1!1:2" into the worksheet index and the 0-based first and last rows.
+// A single row like "1" is a range of one row. The worksheet index is AllSheets when the reference has no worksheet name.
+func ParseRowRange(ref string) (sheet, first, last int, err error) {
+	sheet, rows, err := splitSheetReference(ref)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	m := rowRangeRe.FindStringSubmatch(rows)
+	if m == nil {
+		return 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if m[2] == "" {
+		m[2] = m[1]
+	}
+	first, _ = strconv.Atoi(m[1])
+	last, _ = strconv.Atoi(m[2])
+	if first < 1 || last < 1 || first > 65536 || last > 65536 {
+		return 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if first > last {
+		first, last = last, first
+	}
+
+	return sheet, first - 1, last - 1, nil
+}
+
+// ParseColumnRange converts a column range such as "A:B" or "worksheet1!A:B" into the worksheet index and the 0-based first and last columns.
+// A single column like "A" is a range of one column. The worksheet index is AllSheets when the reference has no worksheet name.
+func ParseColumnRange(ref string) (sheet, first, last int, err error) {
+	sheet, columns, err := splitSheetReference(ref)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	m := columnRangeRe.FindStringSubmatch(columns)
+	if m == nil {
+		return 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if m[2] == "" {
+		m[2] = m[1]
+	}
+	if first, err = ParseColumn(m[1]); err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if last, err = ParseColumn(m[2]); err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: %q", ErrInvalidRange, ref)
+	}
+	if first > last {
+		first, last = last, first
+	}
+
+	return sheet, first, last, nil
+}
@@ -0,0 +1,101 @@
+package xls
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SanitizePolicy defines what happens to the csv values that Excel could run as formulas,
+// i.e. the values starting with "=", "+", "-", "@", tab or carriage return
+type SanitizePolicy int
+
+const (
+	// SanitizeNone keeps the values as they are
+	SanitizeNone SanitizePolicy = iota
+	// SanitizeEscape writes the values as text with the quote prefix of the cell format, Excel shows them as they are
+	SanitizeEscape
+	// SanitizeStrip removes the leading "=", "@", tab and carriage return, a "-" or "+" stays and the value is text
+	// unless the rest is a plain number, e.g. "=-42" becomes the number -42 and "=+A1" the text "+A1"
+	SanitizeStrip
+	// SanitizeReject stops the conversion with SanitizeError
+	SanitizeReject
+)
+
+var sanitizePolicyNames = map[SanitizePolicy]string{
+	SanitizeNone:   "none",
+	SanitizeEscape: "escape",
+	SanitizeStrip:  "strip",
+	SanitizeReject: "reject",
+}
+
+// String ...
+func (p SanitizePolicy) String() string {
+	if name, ok := sanitizePolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("SanitizePolicy(%d)", int(p))
+}
+
+// ParseSanitizePolicy converts a policy name such as "escape" or "reject" into a SanitizePolicy
+func ParseSanitizePolicy(name string) (SanitizePolicy, error) {
+	for p, n := range sanitizePolicyNames {
+		if strings.EqualFold(n, name) {
+			return p, nil
+		}
+	}
+	return SanitizeNone, fmt.Errorf("%w: %q", ErrInvalidSanitizePolicy, name)
+}
+
+// SanitizedCell is a csv value caught by the sanitization, the escaped values keep their text
+type SanitizedCell struct {
+	Row       int // 0-based csv row index
+	Column    int // 0-based column index
+	Original  string
+	Sanitized string
+}
+
+// formulaPrefixes are the characters that make Excel treat a value as a formula
+const formulaPrefixes = "=+-@\t\r"
+
+// formulaTriggers are the formula prefixes SanitizeStrip removes, the signs are kept to not alter the numbers
+const formulaTriggers = "=@\t\r"
+
+// sanitizer applies the SanitizePolicy to the csv values and collects the altered ones
+type sanitizer struct {
+	policy SanitizePolicy
+	parser *cellParser // Plain numbers like "-42" are safe and kept as they are
+	report []SanitizedCell
+}
+
+// isFormulaLike returns true if Excel could run the value as a formula, plain numbers like "-42" are safe
+func isFormulaLike(parser *cellParser, value string) bool {
+	if value == "" || !strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return false
+	}
+	_, isNumber := parser.parseNumber(value)
+	return !isNumber
+}
+
+// sanitize returns the value to write. The escaped values and the stripped values still starting with a sign are kept,
+// the worksheet writes them with the quote prefix.
+func (s *sanitizer) sanitize(rowIdx, columnIdx int, value string) (string, error) {
+	if s.policy == SanitizeNone || !isFormulaLike(s.parser, value) {
+		return value, nil
+	}
+
+	var sanitized string
+	switch s.policy {
+	case SanitizeEscape:
+		sanitized = value
+	case SanitizeStrip:
+		sanitized = strings.TrimLeft(value, formulaTriggers)
+	case SanitizeReject:
+		return "", &SanitizeError{rowIdx, columnIdx, value}
+	default:
+		return value, nil
+	}
+
+	s.report = append(s.report, SanitizedCell{rowIdx, columnIdx, value, sanitized})
+
+	return sanitized, nil
+}
@@ -0,0 +1,131 @@
+package xls
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	converter, err := NewCsv2XlsStreamConverter(";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := converter.newCellParser()
+
+	// formulaLike is true if the sanitized value still starts like a formula,
+	// with escape and strip the worksheet writes such a value as text with the quote prefix
+	tests := []struct {
+		policy      SanitizePolicy
+		value       string
+		sanitized   string
+		reported    bool
+		formulaLike bool
+	}{
+		{SanitizeEscape, "=-42", "=-42", true, true},
+		{SanitizeEscape, "-42", "-42", false, false},
+		{SanitizeEscape, "@SUM(A1)", "@SUM(A1)", true, true},
+		{SanitizeEscape, "\t=cmd|' /C calc'!A0", "\t=cmd|' /C calc'!A0", true, true},
+		{SanitizeEscape, "plain", "plain", false, false},
+
+		{SanitizeStrip, "=-42", "-42", true, false},
+		{SanitizeStrip, "-42", "-42", false, false},
+		{SanitizeStrip, "+1.5", "+1.5", false, false},
+		{SanitizeStrip, "@SUM(A1)", "SUM(A1)", true, false},
+		{SanitizeStrip, "\t=cmd|' /C calc'!A0", "cmd|' /C calc'!A0", true, false},
+		{SanitizeStrip, "\r\n=1+2", "\n=1+2", true, false},
+		{SanitizeStrip, "=+A1", "+A1", true, true},
+		{SanitizeStrip, "-2+3", "-2+3", true, true},
+		{SanitizeStrip, "plain", "plain", false, false},
+
+		{SanitizeNone, "=-42", "=-42", false, true},
+	}
+
+	for _, tt := range tests {
+		s := &sanitizer{policy: tt.policy, parser: parser}
+		sanitized, err := s.sanitize(1, 2, tt.value)
+		if err != nil {
+			t.Errorf("%s %q: error %v", tt.policy, tt.value, err)
+			continue
+		}
+		if sanitized != tt.sanitized {
+			t.Errorf("%s %q = %q, want %q", tt.policy, tt.value, sanitized, tt.sanitized)
+		}
+		if formulaLike := isFormulaLike(parser, sanitized); formulaLike != tt.formulaLike {
+			t.Errorf("%s %q: formula-like = %v, want %v", tt.policy, tt.value, formulaLike, tt.formulaLike)
+		}
+
+		var want []SanitizedCell
+		if tt.reported {
+			want = []SanitizedCell{{1, 2, tt.value, tt.sanitized}}
+		}
+		if !reflect.DeepEqual(s.report, want) {
+			t.Errorf("%s %q: report %v, want %v", tt.policy, tt.value, s.report, want)
+		}
+	}
+}
+
+func TestSanitizeStripKeepsTheSign(t *testing.T) {
+	converter, err := NewCsv2XlsStreamConverter(";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := converter.newCellParser()
+
+	s := &sanitizer{policy: SanitizeStrip, parser: parser}
+	sanitized, err := s.sanitize(0, 0, "=-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cell := parser.parse(0, sanitized); cell.kind != cellKindNumber || cell.num != -42 {
+		t.Errorf("=-42 is written as %+v, want the number -42", cell)
+	}
+}
+
+func TestSanitizeReject(t *testing.T) {
+	converter, err := NewCsv2XlsStreamConverter(";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &sanitizer{policy: SanitizeReject, parser: converter.newCellParser()}
+
+	for _, value := range []string{"-42", "plain"} {
+		if sanitized, err := s.sanitize(0, 0, value); err != nil || sanitized != value {
+			t.Errorf("reject %q = %q, %v", value, sanitized, err)
+		}
+	}
+
+	_, err = s.sanitize(3, 1, "@SUM(A1)")
+	var sanitizeErr *SanitizeError
+	if !errors.As(err, &sanitizeErr) || *sanitizeErr != (SanitizeError{3, 1, "@SUM(A1)"}) {
+		t.Errorf("reject @SUM(A1) = %v, want SanitizeError", err)
+	}
+	if len(s.report) != 0 {
+		t.Errorf("report %v, want empty", s.report)
+	}
+}
+
+func TestSanitizationReport(t *testing.T) {
+	converter, err := NewCsv2XlsStreamConverter(";")
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter.WithSanitization(SanitizeStrip)
+
+	csv := "name;value\n=-42;-42\n@SUM(A1);\t=cmd|' /C calc'!A0\n"
+	if err := converter.ConvertStream(context.Background(), strings.NewReader(csv), ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SanitizedCell{
+		{1, 0, "=-42", "-42"},
+		{2, 0, "@SUM(A1)", "SUM(A1)"},
+		{2, 1, "\t=cmd|' /C calc'!A0", "cmd|' /C calc'!A0"},
+	}
+	if report := converter.SanitizationReport(); !reflect.DeepEqual(report, want) {
+		t.Errorf("SanitizationReport() = %q, want %q", report, want)
+	}
+}
@@ -0,0 +1,94 @@
+package xls
+
+// firstCustomFormatIndex is the index of the first user defined number format, lower indexes are built-in formats
+const firstCustomFormatIndex = 164
+
+// firstCellXfIndex is the index of the first cell XF record, lower indexes are the style XF records
+const firstCellXfIndex = 15
+
+// fontStyle describes a FONT record, the zero value is the default font
+type fontStyle struct {
+	bold      bool
+	hyperlink bool // Blue and underlined
+}
+
+// cellStyle describes the formatting of a cell, every distinct cellStyle is written as a cell XF record
+type cellStyle struct {
+	font     uint16 // Index of the font
+	format   uint16 // Index of the number format
+	unlocked bool   // The cell can be edited on a protected worksheet
+	hidden   bool   // The formula of the cell is not shown on a protected worksheet
+	quoted   bool   // The text has the quote prefix, Excel never treats it as a formula
+}
+
+// styleTable collects the fonts, number formats and cell XF records used by the worksheets
+type styleTable struct {
+	fonts     []fontStyle
+	fontMap   map[fontStyle]uint16
+	formats   []string
+	formatMap map[string]uint16
+	xfs       []cellStyle
+	xfMap     map[cellStyle]int
+}
+
+func newStyleTable() *styleTable {
+	st := &styleTable{
+		fonts:     make([]fontStyle, 0),
+		fontMap:   make(map[fontStyle]uint16),
+		formats:   make([]string, 0),
+		formatMap: make(map[string]uint16),
+		xfs:       make([]cellStyle, 0),
+		xfMap:     make(map[cellStyle]int),
+	}
+	// The default font and cell format, written into every cell before the styles were introduced
+	st.font(fontStyle{})
+	st.xfIndex(cellStyle{})
+
+	return st
+}
+
+// font returns the index of the font, registering it if needed
+func (st *styleTable) font(font fontStyle) uint16 {
+	if idx, ok := st.fontMap[font]; ok {
+		return idx
+	}
+
+	// There is no font with index 4, the fonts after the fourth one are shifted by one
+	idx := uint16(len(st.fonts))
+	if idx >= 4 {
+		idx++
+	}
+	st.fonts = append(st.fonts, font)
+	st.fontMap[font] = idx
+
+	return idx
+}
+
+// numberFormat returns the index of the number format code, registering it if needed
+func (st *styleTable) numberFormat(code string) uint16 {
+	if code == "" || code == "General" {
+		return 0
+	}
+	if idx, ok := st.formatMap[code]; ok {
+		return idx
+	}
+
+	idx := uint16(firstCustomFormatIndex + len(st.formats))
+	st.formats = append(st.formats, code)
+	st.formatMap[code] = idx
+
+	return idx
+}
+
+// xfIndex returns the index of the cell XF record of style, registering it if needed
+func (st *styleTable) xfIndex(style cellStyle) int {
+	if idx, ok := st.xfMap[style]; ok {
+		return idx
+	}
+
+	idx := firstCellXfIndex + len(st.xfs)
+	st.xfs = append(st.xfs, style)
+	st.xfMap[style] = idx
+
+	return idx
+}
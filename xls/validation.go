@@ -0,0 +1,331 @@
+package xls
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationType is the kind of values allowed by a data validation rule
+type ValidationType int
+
+const (
+	// ValidationList allows the values of a drop-down list
+	ValidationList ValidationType = iota
+	// ValidationWhole allows the whole numbers
+	ValidationWhole
+	// ValidationDecimal allows the numbers
+	ValidationDecimal
+	// ValidationDate allows the dates
+	ValidationDate
+	// ValidationTextLength allows the values of a length
+	ValidationTextLength
+)
+
+var validationTypeNames = map[ValidationType]string{
+	ValidationList:       "list",
+	ValidationWhole:      "whole",
+	ValidationDecimal:    "decimal",
+	ValidationDate:       "date",
+	ValidationTextLength: "textlength",
+}
+
+// validationTypeCodes are the DV record codes of the validation types
+var validationTypeCodes = map[ValidationType]uint32{
+	ValidationWhole:      1,
+	ValidationDecimal:    2,
+	ValidationList:       3,
+	ValidationDate:       4,
+	ValidationTextLength: 6,
+}
+
+// String ...
+func (t ValidationType) String() string {
+	if name, ok := validationTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ValidationType(%d)", int(t))
+}
+
+// ValidationOperator compares a value with the bounds of a data validation rule
+type ValidationOperator int
+
+const (
+	// ValidationBetween allows the values from Value1 to Value2
+	ValidationBetween ValidationOperator = iota
+	// ValidationNotBetween allows the values out of Value1 - Value2
+	ValidationNotBetween
+	// ValidationEqual allows Value1
+	ValidationEqual
+	// ValidationNotEqual allows any value but Value1
+	ValidationNotEqual
+	// ValidationGreater allows the values greater than Value1
+	ValidationGreater
+	// ValidationLess allows the values less than Value1
+	ValidationLess
+	// ValidationGreaterOrEqual allows the values greater than or equal to Value1
+	ValidationGreaterOrEqual
+	// ValidationLessOrEqual allows the values less than or equal to Value1
+	ValidationLessOrEqual
+)
+
+var validationOperatorNames = map[ValidationOperator]string{
+	ValidationBetween:        "between",
+	ValidationNotBetween:     "notbetween",
+	ValidationEqual:          "=",
+	ValidationNotEqual:       "<>",
+	ValidationGreater:        ">",
+	ValidationLess:           "<",
+	ValidationGreaterOrEqual: ">=",
+	ValidationLessOrEqual:    "<=",
+}
+
+// String ...
+func (o ValidationOperator) String() string {
+	if name, ok := validationOperatorNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("ValidationOperator(%d)", int(o))
+}
+
+// ValidationErrorStyle is the reaction of Excel on an invalid value
+type ValidationErrorStyle int
+
+const (
+	// ValidationStop rejects the invalid value
+	ValidationStop ValidationErrorStyle = iota
+	// ValidationWarning asks whether to keep the invalid value
+	ValidationWarning
+	// ValidationInformation keeps the invalid value after the message
+	ValidationInformation
+)
+
+var validationErrorStyleNames = map[ValidationErrorStyle]string{
+	ValidationStop:        "stop",
+	ValidationWarning:     "warning",
+	ValidationInformation: "information",
+}
+
+// String ...
+func (s ValidationErrorStyle) String() string {
+	if name, ok := validationErrorStyleNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("ValidationErrorStyle(%d)", int(s))
+}
+
+// DataValidation is a data validation rule of a cell range
+type DataValidation struct {
+	Range    CellRange
+	Type     ValidationType
+	Operator ValidationOperator // Not used by ValidationList
+
+	// List is the values of ValidationList, a formula like "=$F$1:$F$5" in Value1 can be used instead
+	List []string
+	// Value1 and Value2 are the bounds of the rule: numbers, dates like "2024-01-31" or formulas starting with "="
+	Value1 string
+	Value2 string // Used only by ValidationBetween and ValidationNotBetween
+
+	AllowBlank   bool // Empty cells are valid
+	InputTitle   string
+	InputMessage string // Shown when a cell of the range is selected
+	ErrorStyle   ValidationErrorStyle
+	ErrorTitle   string
+	ErrorMessage string // Shown when an invalid value is entered
+}
+
+// ParseDataValidation converts a rule like "B2:B100=list:Open,Closed|error=Pick a status" into the worksheet index and the rule.
+// The rule is range=type:values, where the values are "v1,v2,..." or "=formula" for the list type,
+// and "operator:value1[:value2]" for the whole, decimal, date and textlength types, e.g. "C2:C100=whole:between:1:10".
+// The options after "|" are input-title, input, error-title, error, error-style (stop, warning, information) and allow-blank (true, false).
+// The blank cells are allowed unless allow-blank=false. The worksheet index is AllSheets when the range has no worksheet name.
+func ParseDataValidation(rule string) (int, DataValidation, error) {
+	invalid := func() (int, DataValidation, error) {
+		return 0, DataValidation{}, fmt.Errorf("%w: %q", ErrInvalidDataValidation, rule)
+	}
+
+	options := strings.Split(rule, "|")
+	parts := strings.SplitN(options[0], "=", 2)
+	if len(parts) != 2 {
+		return invalid()
+	}
+
+	sheet, r, err := ParseRange(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, DataValidation{}, err
+	}
+	dv := DataValidation{Range: r, AllowBlank: true}
+
+	args := strings.SplitN(parts[1], ":", 2)
+	typeFound := false
+	for t, name := range validationTypeNames {
+		if strings.EqualFold(name, strings.TrimSpace(args[0])) {
+			dv.Type, typeFound = t, true
+		}
+	}
+	if !typeFound || len(args) != 2 {
+		return invalid()
+	}
+
+	if dv.Type == ValidationList {
+		if strings.HasPrefix(args[1], "=") {
+			dv.Value1 = args[1]
+		} else {
+			dv.List = strings.Split(args[1], ",")
+		}
+	} else {
+		values := strings.Split(args[1], ":")
+		operatorFound := false
+		for o, name := range validationOperatorNames {
+			if strings.EqualFold(name, strings.TrimSpace(values[0])) {
+				dv.Operator, operatorFound = o, true
+			}
+		}
+		between := dv.Operator == ValidationBetween || dv.Operator == ValidationNotBetween
+		if !operatorFound || (between && len(values) != 3) || (!between && len(values) != 2) {
+			return invalid()
+		}
+		dv.Value1 = values[1]
+		if between {
+			dv.Value2 = values[2]
+		}
+	}
+
+	for _, option := range options[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return invalid()
+		}
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "input-title":
+			dv.InputTitle = kv[1]
+		case "input":
+			dv.InputMessage = kv[1]
+		case "error-title":
+			dv.ErrorTitle = kv[1]
+		case "error":
+			dv.ErrorMessage = kv[1]
+		case "error-style":
+			styleFound := false
+			for s, name := range validationErrorStyleNames {
+				if strings.EqualFold(name, strings.TrimSpace(kv[1])) {
+					dv.ErrorStyle, styleFound = s, true
+				}
+			}
+			if !styleFound {
+				return invalid()
+			}
+		case "allow-blank":
+			if dv.AllowBlank, err = strconv.ParseBool(strings.TrimSpace(kv[1])); err != nil {
+				return invalid()
+			}
+		default:
+			return invalid()
+		}
+	}
+
+	return sheet, dv, nil
+}
+
+// dataValidation is a DataValidation with the compiled formulas
+type dataValidation struct {
+	DataValidation
+	formula1 []byte
+	formula2 []byte
+}
+
+// compileDataValidation checks the rule and compiles its bounds
+func compileDataValidation(dv DataValidation, sheetNames []string) (dataValidation, error) {
+	invalid := func(reason string) (dataValidation, error) {
+		return dataValidation{}, fmt.Errorf("%w: %s %s: %s", ErrInvalidDataValidation, dv.Type, dv.Range, reason)
+	}
+
+	if _, ok := validationTypeCodes[dv.Type]; !ok {
+		return invalid("unknown type")
+	}
+	if _, ok := validationOperatorNames[dv.Operator]; !ok {
+		return invalid("unknown operator")
+	}
+	if _, ok := validationErrorStyleNames[dv.ErrorStyle]; !ok {
+		return invalid("unknown error style")
+	}
+	if !dv.Range.isValid() {
+		return invalid("range out of the worksheet")
+	}
+	if utf16Length(dv.InputTitle) > 32 || utf16Length(dv.ErrorTitle) > 32 {
+		return invalid("titles are limited to 32 characters")
+	}
+	if utf16Length(dv.InputMessage) > 255 || utf16Length(dv.ErrorMessage) > 255 {
+		return invalid("messages are limited to 255 characters")
+	}
+
+	compiled := dataValidation{DataValidation: dv}
+
+	// The explicit list is a single string with the values separated by zero characters
+	if dv.Type == ValidationList && dv.Value1 == "" {
+		list := strings.Join(dv.List, "\x00")
+		if len(dv.List) == 0 || utf16Length(list) > 255 {
+			return invalid("the list must have 1 - 255 characters")
+		}
+		buf := new(bytes.Buffer)
+		putVar(buf, uint8(ptgStr), []byte(utf8toBIFF8UnicodeShort(list)))
+		compiled.formula1 = buf.Bytes()
+
+		return compiled, nil
+	}
+
+	var err error
+	if compiled.formula1, err = compileValidationValue(dv.Type, dv.Value1, sheetNames); err != nil {
+		return invalid(err.Error())
+	}
+	if dv.Type != ValidationList && (dv.Operator == ValidationBetween || dv.Operator == ValidationNotBetween) {
+		if compiled.formula2, err = compileValidationValue(dv.Type, dv.Value2, sheetNames); err != nil {
+			return invalid(err.Error())
+		}
+	}
+
+	return compiled, nil
+}
+
+// compileValidationValue compiles a bound of a rule: a formula, a date or a number
+func compileValidationValue(t ValidationType, value string, sheetNames []string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("missing value")
+	}
+	if strings.HasPrefix(value, "=") {
+		return compileFormula(value[1:], sheetNames)
+	}
+
+	if t == ValidationDate {
+		d, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", value)
+		}
+		serial, ok := excelSerialDate(d)
+		if !ok {
+			return nil, fmt.Errorf("date %q out of range", value)
+		}
+		value = strconv.FormatFloat(serial, 'f', -1, 64)
+	} else if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return nil, fmt.Errorf("invalid number %q", value)
+	}
+
+	return compileFormula(value, sheetNames)
+}
+
+// writeDataValidationString writes a DV record string, Excel expects a zero character for an empty string
+func writeDataValidationString(buffer *bytes.Buffer, str string) {
+	if str == "" {
+		putVar(buffer, uint16(1), uint8(0), uint8(0))
+		return
+	}
+	putVar(buffer, []byte(utf8toBIFF8UnicodeLong(str)))
+}
+
+// writeDataValidationFormula writes a DV record formula with its length
+func writeDataValidationFormula(buffer *bytes.Buffer, rgce []byte) {
+	putVar(buffer, uint16(len(rgce)), uint16(0), rgce)
+}
@@ -0,0 +1,64 @@
+package xls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompileDataValidationLengths(t *testing.T) {
+	r := CellRange{FirstRow: 1, LastRow: 10}
+	list := func(values ...string) DataValidation {
+		return DataValidation{Range: r, Type: ValidationList, List: values}
+	}
+	withText := func(dv DataValidation, inputTitle, inputMessage string) DataValidation {
+		dv.InputTitle, dv.InputMessage = inputTitle, inputMessage
+		return dv
+	}
+
+	// A surrogate pair is two UTF-16 characters
+	tests := []struct {
+		name  string
+		dv    DataValidation
+		valid bool
+	}{
+		{"list of 255", list(strings.Repeat("a", 127), strings.Repeat("b", 127)), true},
+		{"list of 254 with pairs", list(strings.Repeat("😀", 127)), true},
+		{"list of 256 with pairs", list(strings.Repeat("😀", 128)), false},
+		{"title of 32 with pairs", withText(list("a"), strings.Repeat("😀", 16), ""), true},
+		{"title of 34 with pairs", withText(list("a"), strings.Repeat("😀", 17), ""), false},
+		{"message of 256 with pairs", withText(list("a"), "", strings.Repeat("😀", 128)), false},
+	}
+
+	for _, tt := range tests {
+		_, err := compileDataValidation(tt.dv, []string{"worksheet"})
+		if tt.valid && err != nil || !tt.valid && !errors.Is(err, ErrInvalidDataValidation) {
+			t.Errorf("%s: compileDataValidation() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestCompileDataValidationListNonBMP(t *testing.T) {
+	compiled, err := compileDataValidation(DataValidation{Range: CellRange{}, Type: ValidationList, List: []string{"😀", "b"}}, []string{"worksheet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ptgStr, the UTF-16 length, the option flags and "😀\x00b" as UTF-16
+	want := []byte{ptgStr, 4, 1, 0x3D, 0xD8, 0x00, 0xDE, 0, 0, 'b', 0}
+	if !bytes.Equal(compiled.formula1, want) {
+		t.Errorf("list formula = % x, want % x", compiled.formula1, want)
+	}
+}
+
+func TestWriteDataValidationString(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	writeDataValidationString(buffer, "a😀")
+
+	data := buffer.Bytes()
+	if cch := int(binary.LittleEndian.Uint16(data)); cch != 3 || len(data) != 3+2*cch {
+		t.Errorf("string % x has %d characters", data, cch)
+	}
+}
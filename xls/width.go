@@ -0,0 +1,104 @@
+package xls
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxColumnWidth is the maximum width of the automatically fitted columns in characters
+const DefaultMaxColumnWidth = 50
+
+// minAutoColumnWidth is the minimum width of the automatically fitted columns in characters
+const minAutoColumnWidth = 8
+
+// maxColumnWidth is the widest column Excel accepts in characters
+const maxColumnWidth = 255
+
+// wideRanges are the East Asian wide and fullwidth characters, they take two character cells
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK Radicals, Kangxi Radicals, CJK Symbols and Punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, Hangul Compatibility Jamo, CJK Compatibility
+	{0x3400, 0x4DBF},   // CJK Unified Ideographs Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi Syllables and Radicals
+	{0xAC00, 0xD7A3},   // Hangul Syllables
+	{0xF900, 0xFAFF},   // CJK Compatibility Ideographs
+	{0xFE30, 0xFE4F},   // CJK Compatibility Forms
+	{0xFF00, 0xFF60},   // Fullwidth Forms
+	{0xFFE0, 0xFFE6},   // Fullwidth Signs
+	{0x1F300, 0x1F64F}, // Miscellaneous Symbols and Pictographs, Emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental Symbols and Pictographs
+	{0x20000, 0x2FFFD}, // CJK Unified Ideographs Extension B - F
+	{0x30000, 0x3FFFD}, // CJK Unified Ideographs Extension G
+}
+
+// runeWidth returns the number of character cells taken by r
+func runeWidth(r rune) int {
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) {
+		return 0
+	}
+	for _, wr := range wideRanges {
+		if r < wr[0] {
+			break
+		}
+		if r <= wr[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// displayWidth returns the number of character cells taken by the longest line of str
+func displayWidth(str string) int {
+	width := 0
+	for _, line := range strings.Split(str, "\n") {
+		w := 0
+		for _, r := range line {
+			w += runeWidth(r)
+		}
+		width = max(width, w)
+	}
+	return width
+}
+
+// renderedWidth returns the number of character cells taken by the cell value as Excel shows it
+func (cell cellValue) renderedWidth() int {
+	switch cell.kind {
+	case cellKindNumber:
+		if cell.format != "" {
+			return displayWidth(cell.format)
+		}
+		// General format shows up to 11 characters
+		return min(len(strconv.FormatFloat(cell.num, 'f', -1, 64)), 11)
+	case cellKindBoolean:
+		if cell.code != 0 {
+			return len("TRUE")
+		}
+		return len("FALSE")
+	case cellKindError:
+		for token, code := range errorCodes {
+			if code == cell.code {
+				return len(token)
+			}
+		}
+	case cellKindString:
+		return displayWidth(cell.str)
+	}
+	return 0
+}
+
+// fitColumnWidth returns the column width in characters for the widest value,
+// the maximum width wins over the minimum one
+func fitColumnWidth(width, maxWidth int) int {
+	// One character of padding, the cell text does not touch the borders
+	width++
+	if width < minAutoColumnWidth {
+		width = minAutoColumnWidth
+	}
+	if width > maxWidth {
+		width = maxWidth
+	}
+	return width
+}
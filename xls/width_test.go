@@ -0,0 +1,55 @@
+package xls
+
+import "testing"
+
+func TestFitColumnWidth(t *testing.T) {
+	tests := []struct {
+		width    int
+		maxWidth int
+		want     int
+	}{
+		{0, DefaultMaxColumnWidth, minAutoColumnWidth},
+		{3, DefaultMaxColumnWidth, minAutoColumnWidth},
+		{10, DefaultMaxColumnWidth, 11}, // One character of padding
+		{49, DefaultMaxColumnWidth, 50},
+		{100, DefaultMaxColumnWidth, 50},
+		{300, maxColumnWidth, maxColumnWidth},
+		{3, 5, 5},  // The maximum wins over the minimum
+		{20, 5, 5}, // The maximum caps the wide values
+		{0, 1, 1},  // The narrowest maximum
+		{6, 10, 8}, // The minimum below the maximum
+		{12, 10, 10},
+	}
+
+	for _, tt := range tests {
+		if got := fitColumnWidth(tt.width, tt.maxWidth); got != tt.want {
+			t.Errorf("fitColumnWidth(%d, %d) = %d, want %d", tt.width, tt.maxWidth, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		str  string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"Grüße", 5},
+		{"e\u0301", 1}, // A combining accent takes no cell
+		{"日本語", 6},     // CJK ideographs take two cells
+		{"한국어", 6},     // Hangul syllables
+		{"ｶﾀｶﾅ", 4},    // Halfwidth katakana take one cell
+		{"ＡＢ", 4},      // Fullwidth forms
+		{"a日b", 4},
+		{"😀", 2},
+		{"short\n日本語日本語", 12}, // The longest line
+		{"日本\nlonger line", 11},
+	}
+
+	for _, tt := range tests {
+		if got := displayWidth(tt.str); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.str, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-package app
+package xls
 
 import (
 	"bytes"
@@ -80,6 +80,34 @@ type workbook struct {
 	WorksheetSizes   []int
 	WorksheetNames   []string
 	stringCollection *stringCollection
+	styles           *styleTable
+	definedNames     []definedName
+	drawings         *drawingGroup
+	protection       *workbookProtection // nil when the workbook structure is not protected
+	fileSharing      *fileSharing        // nil when the workbook is not write-reserved
+	encryption       *rc4Encryption      // nil when the workbook is not encrypted
+}
+
+// Built-in defined names
+const (
+	builtinPrintArea      byte = 0x06
+	builtinPrintTitles    byte = 0x07
+	builtinFilterDatabase byte = 0x0D
+)
+
+// definedName is a built-in name of a worksheet
+type definedName struct {
+	builtin byte   // Built-in name code
+	sheet   int    // 0-based index of the worksheet
+	hidden  bool   // The name is not shown in the Name Manager
+	rgce    []byte // Parsed formula of the name
+}
+
+// area3d returns the parsed formula of the cells of the worksheet with 0-based index sheet
+func area3d(sheet int, r CellRange) []byte {
+	buf := new(bytes.Buffer)
+	putVar(buf, uint8(ptgArea3d), uint16(sheet), uint16(r.FirstRow), uint16(r.LastRow), uint16(r.FirstColumn), uint16(r.LastColumn))
+	return buf.Bytes()
 }
 
 func (wb *workbook) getWorksheetSizesData() string {
@@ -91,7 +119,13 @@ func (wb *workbook) getWorksheetSizesData() string {
 
 	// Add part 1 of the workbook globals, what goes before the SHEET records
 	wb.storeBof(buf)
+	wb.writeWriteProtect(buf)
+	wb.writeFilePass(buf)
+	wb.writeFileSharing(buf)
 	wb.writeCodepage(buf)
+	wb.writeWindowProtect(buf)
+	wb.writeProtect(buf)
+	wb.writePassword(buf)
 	wb.writeWindow1(buf)
 
 	wb.writeDateMode(buf)
@@ -146,6 +180,84 @@ func (wb *workbook) storeBof(buffer *bytes.Buffer) {
 	putVar(buffer, uint32(0x000100D1), uint32(0x00000406))
 }
 
+func (wb *workbook) writeWriteProtect(buffer *bytes.Buffer) {
+	if wb.fileSharing == nil || wb.fileSharing.modifyPassword == "" {
+		return
+	}
+
+	var record uint16 = 0x0086 // Record identifier
+	var length uint16 = 0x0000 // Bytes to follow
+
+	putVar(buffer, record, length)
+}
+
+func (wb *workbook) writeFilePass(buffer *bytes.Buffer) {
+	if wb.encryption != nil {
+		wb.encryption.writeFilePass(buffer)
+	}
+}
+
+func (wb *workbook) writeFileSharing(buffer *bytes.Buffer) {
+	if wb.fileSharing == nil {
+		return
+	}
+
+	var record uint16 = 0x005B // Record identifier
+
+	var fReadOnlyRec uint16 = 0 // Read-only recommended
+	if wb.fileSharing.readOnlyRecommended {
+		fReadOnlyRec = 1
+	}
+	wResPassNum := passwordHash(wb.fileSharing.modifyPassword) // Encoded password to modify
+	userName := utf8toBIFF8UnicodeLong(wb.fileSharing.userName)
+	length := uint16(4 + len(userName)) // Bytes to follow
+
+	putVar(buffer, record, length)
+	putVar(buffer, fReadOnlyRec, wResPassNum, []byte(userName))
+}
+
+func (wb *workbook) writeWindowProtect(buffer *bytes.Buffer) {
+	if wb.protection == nil {
+		return
+	}
+
+	var record uint16 = 0x0019 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fLockWn uint16 = 0 // The windows are protected
+	if wb.protection.windows {
+		fLockWn = 1
+	}
+
+	putVar(buffer, record, length, fLockWn)
+}
+
+func (wb *workbook) writeProtect(buffer *bytes.Buffer) {
+	if wb.protection == nil {
+		return
+	}
+
+	var record uint16 = 0x0012 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fLock uint16 = 1 // The structure is protected
+
+	putVar(buffer, record, length, fLock)
+}
+
+func (wb *workbook) writePassword(buffer *bytes.Buffer) {
+	if wb.protection == nil {
+		return
+	}
+
+	var record uint16 = 0x0013 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	wPassword := passwordHash(wb.protection.password) // Encoded password, zero without password
+
+	putVar(buffer, record, length, wPassword)
+}
+
 func (wb *workbook) writeCodepage(buffer *bytes.Buffer) {
 	var record uint16 = 0x0042 // Record identifier
 	var length uint16 = 0x0002 // Number of bytes to follow
@@ -197,45 +309,85 @@ func (wb *workbook) writeAllFonts(buffer *bytes.Buffer) {
 	var reserved uint8 = 0x00 // Reserved
 	var grbit uint16 = 0x00   // Font attributes
 
-	dataBuf := new(bytes.Buffer)
-
-	var fontSize uint16 = 11
-	putVar(dataBuf,
-		fontSize*20,
-		grbit,
-		icv,           // Colour
-		uint16(0x190), // Font weight (0x190=400=normal)
-		sss,           // Superscript/Subscript
-		uint8(0x00),   // Underline
-		bFamily,
-		bCharSet,
-		reserved,
-		[]byte(utf8toBIFF8UnicodeShort("Calibri")),
-	)
+	for _, font := range wb.styles.fonts {
+		var bls uint16 = 0x190 // Font weight (0x190=400=normal)
+		if font.bold {
+			bls = 0x2BC // 0x2BC=700=bold
+		}
+		icv, uls := icv, uint8(0x00)
+		if font.hyperlink {
+			icv, uls = 0x0C, 0x01 // Blue, single underline
+		}
 
-	putVar(buffer, record, uint16(dataBuf.Len()))
-	buffer.Write(dataBuf.Bytes())
+		dataBuf := new(bytes.Buffer)
+
+		var fontSize uint16 = 11
+		putVar(dataBuf,
+			fontSize*20,
+			grbit,
+			icv, // Colour
+			bls, // Font weight
+			sss, // Superscript/Subscript
+			uls, // Underline
+			bFamily,
+			bCharSet,
+			reserved,
+			[]byte(utf8toBIFF8UnicodeShort("Calibri")),
+		)
+
+		putVar(buffer, record, uint16(dataBuf.Len()))
+		buffer.Write(dataBuf.Bytes())
+	}
 }
 
 func (wb *workbook) writeAllNumberFormats(buffer *bytes.Buffer) {
-	// empty
+	var record uint16 = 0x041E // Record identifier
+
+	for i, code := range wb.styles.formats {
+		recordData := utf8toBIFF8UnicodeLong(code)
+		length := uint16(2 + len(recordData))
+
+		putVar(buffer, record, length)
+		putVar(buffer, uint16(firstCustomFormatIndex+i), []byte(recordData))
+	}
 }
 
 func (wb *workbook) writeAllXfs(buffer *bytes.Buffer) {
 	var record uint16 = 0x00E0 // Record identifier
 	var length uint16 = 0x0014 // Number of bytes to follow
 
-	for i := 0; i < 15; i++ {
+	for i := 0; i < firstCellXfIndex; i++ {
 		putVar(buffer, record, length)
 		putVar(buffer, uint16(0), uint16(0), uint16(0xFFF5), uint8(32))
 		putVar(buffer, uint8(0), uint8(0), uint8(0xC0))
 		putVar(buffer, uint32(0), uint32(0), uint16(1033))
 	}
 
-	putVar(buffer, record, length)
-	putVar(buffer, uint16(0), uint16(0), uint16(1), uint8(32))
-	putVar(buffer, uint8(0), uint8(0), uint8(0xC0))
-	putVar(buffer, uint32(0), uint32(0), uint16(1033))
+	for _, style := range wb.styles.xfs {
+		var usedAttrib uint8 = 0xC0 // Protection and pattern attributes are defined by this XF
+		if style.format != 0 {
+			usedAttrib |= 0x04 // The number format is defined by this XF
+		}
+		if style.font != 0 {
+			usedAttrib |= 0x08 // The font is defined by this XF
+		}
+
+		var protection uint16 = 0x0001 // Locked
+		if style.unlocked {
+			protection = 0x0000
+		}
+		if style.hidden {
+			protection |= 0x0002
+		}
+		if style.quoted {
+			protection |= 0x0008 // f123Prefix
+		}
+
+		putVar(buffer, record, length)
+		putVar(buffer, style.font, style.format, protection, uint8(32))
+		putVar(buffer, uint8(0), uint8(0), usedAttrib)
+		putVar(buffer, uint32(0), uint32(0), uint16(1033))
+	}
 }
 
 func (wb *workbook) writeAllStyles(buffer *bytes.Buffer) {
@@ -305,11 +457,35 @@ func (wb *workbook) writeExternalsheetBiff8(buffer *bytes.Buffer, totalWorksheet
 }
 
 func (wb *workbook) writeAllDefinedNamesBiff8(buffer *bytes.Buffer) {
-	// empty
+	for _, name := range wb.definedNames {
+		wb.writeNameBiff8(buffer, name)
+	}
+}
+
+func (wb *workbook) writeNameBiff8(buffer *bytes.Buffer, name definedName) {
+	var record uint16 = 0x0018 // Record identifier
+
+	var grbit uint16 = 0x0020 // Built-in name
+	if name.hidden {
+		grbit |= 0x0001
+	}
+
+	var chKey uint8 = 0x00                    // Keyboard shortcut
+	var cch uint8 = 0x01                      // Length of the built-in name, it is a single character code
+	cce := uint16(len(name.rgce))             // Length of the formula
+	var ixals uint16 = 0x0000                 // Not used
+	itab := uint16(name.sheet + 1)            // 1-based index of the worksheet of a local name
+	length := uint16(14 + 2 + len(name.rgce)) // Bytes to follow
+
+	putVar(buffer, record, length)
+	putVar(buffer, grbit, chKey, cch, cce, ixals, itab, uint8(0), uint8(0), uint8(0), uint8(0))
+	putVar(buffer, uint8(0x00), name.builtin, name.rgce)
 }
 
 func (wb *workbook) writeMsoDrawingGroup(buffer *bytes.Buffer) {
-	// empty
+	if wb.drawings != nil {
+		wb.drawings.write(buffer)
+	}
 }
 
 func (wb *workbook) writeData(bufferTo *bytes.Buffer, bufferFrom *bytes.Buffer) {
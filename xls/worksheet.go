@@ -0,0 +1,1113 @@
+package xls
+
+import (
+	"bytes"
+	"math"
+	"strings"
+)
+
+const (
+	// maxMergedCellsPerRecord is the number of ranges fitting into a MERGEDCELLS record
+	maxMergedCellsPerRecord = 1027
+	// defaultColumnWidth is the width of the columns in characters
+	defaultColumnWidth = 10
+	// defaultRowHeight is the height of the rows in points
+	defaultRowHeight = 15
+)
+
+// worksheet ...
+type worksheet struct {
+	Name         string
+	Grid         [][]string
+	ColumnWidths map[int]int // Column widths in characters set by the user
+	HeaderRows   int
+
+	autoWidths     map[int]int // Widest rendered values of the columns, nil when the widths are not fitted
+	maxColumnWidth int
+
+	pane            pane
+	autoFilter      bool
+	mergedCells     []CellRange
+	quoteFormulas   bool        // The values Excel could run as formulas are text with the quote prefix
+	hyperlinks      bool        // URLs and email addresses are links
+	linkColumns     map[int]int // Target columns of the columns linked to the targets from a companion column
+	links           []hyperlink
+	noteColumns     map[int]int // Note columns of the columns with the notes from a companion column
+	explicitNotes   []Note      // Notes set by the user, they win over the notes from the note columns
+	notes           []cellNote
+	noteAuthor      string
+	images          []sheetImage
+	firstImageShape int // Index of the shape of the first image in the drawing
+	charts          []Chart
+	validations     []dataValidation
+	protection      *sheetProtection // nil when the worksheet is not protected
+	editRanges      []AllowEditRange
+	cellProtections []cellProtection
+	pageSetup       PageSetup
+	header          HeaderFooter
+	footer          HeaderFooter
+	printArea       *CellRange // nil when the whole used range is printed
+	titleRows       *lineRange // nil when the header rows are repeated on every printed page
+	titleColumns    *lineRange // nil when no columns are repeated on every printed page
+	drawing         *drawing
+	parser          *cellParser
+	styles          *styleTable
+	sheetNames      []string // Names of all the worksheets of the workbook
+}
+
+// pane is the frozen or split part of the worksheet window
+type pane struct {
+	rows  int  // Number of rows above the horizontal split
+	cols  int  // Number of columns to the left of the vertical split
+	split bool // The panes are split and scroll independently instead of being frozen
+}
+
+// isEmpty ...
+func (p pane) isEmpty() bool {
+	return p.rows == 0 && p.cols == 0
+}
+
+// isValid ...
+func (p pane) isValid() bool {
+	return p.rows >= 0 && p.rows <= 65535 && p.cols >= 0 && p.cols <= 255
+}
+
+// activePane returns the pane with the cursor: 0 - bottom right, 1 - top right, 2 - bottom left, 3 - top left
+func (p pane) activePane() uint8 {
+	switch {
+	case p.rows != 0 && p.cols != 0:
+		return 0
+	case p.cols != 0:
+		return 1
+	case p.rows != 0:
+		return 2
+	}
+	return 3
+}
+
+// panes returns the panes of the window in the order of the SELECTION records, the active pane goes last
+func (p pane) panes() []uint8 {
+	switch {
+	case p.rows != 0 && p.cols != 0:
+		return []uint8{1, 2, 0}
+	case p.cols != 0:
+		return []uint8{1}
+	case p.rows != 0:
+		return []uint8{2}
+	}
+	return []uint8{3}
+}
+
+// firstCell returns the row and column of the top left cell of the pane pnn
+func (p pane) firstCell(pnn uint8) (uint16, uint16) {
+	switch pnn {
+	case 0:
+		return uint16(p.rows), uint16(p.cols)
+	case 1:
+		return 0, uint16(p.cols)
+	case 2:
+		return uint16(p.rows), 0
+	}
+	return 0, 0
+}
+
+// columnWidth returns the width of the column in characters
+func (ws *worksheet) columnWidth(columnIdx int) int {
+	if value, ok := ws.ColumnWidths[columnIdx]; ok {
+		return value
+	}
+	if ws.autoWidths != nil {
+		return fitColumnWidth(ws.autoWidths[columnIdx], ws.maxColumnWidth)
+	}
+	return defaultColumnWidth
+}
+
+// cellProtection returns the XF protection flags of the cell
+func (ws *worksheet) cellProtection(rowIdx, columnIdx int) (unlocked, hidden bool) {
+	for _, cp := range ws.cellProtections {
+		r := cp.r
+		if rowIdx >= r.FirstRow && rowIdx <= r.LastRow && columnIdx >= r.FirstColumn && columnIdx <= r.LastColumn {
+			unlocked = unlocked || cp.unlocked
+			hidden = hidden || cp.hidden
+		}
+	}
+	return unlocked, hidden
+}
+
+// maxColumn returns the index of the last column with data
+func (ws *worksheet) maxColumn() int {
+	maxColIdx := 0
+	for _, row := range ws.Grid {
+		maxColIdx = max(maxColIdx, len(row)-1)
+	}
+	return maxColIdx
+}
+
+// autoFilterRange returns the cells of the autofilter, the filter drop-downs are in the last header row
+func (ws *worksheet) autoFilterRange() (CellRange, bool) {
+	if !ws.autoFilter || len(ws.Grid) == 0 {
+		return CellRange{}, false
+	}
+
+	row := max(ws.HeaderRows-1, 0)
+	return CellRange{row, 0, len(ws.Grid) - 1, ws.maxColumn()}, true
+}
+
+// shapes returns the shapes of the drawing of the worksheet with 0-based index sheetIdx
+func (ws *worksheet) shapes(sheetIdx int) []drawingShape {
+	shapes := make([]drawingShape, 0)
+	if r, ok := ws.autoFilterRange(); ok {
+		for col := r.FirstColumn; col <= r.LastColumn; col++ {
+			shapes = append(shapes, filterDropDown(r.FirstRow, col))
+		}
+	}
+
+	// The OBJ record ids of the shapes go from 1 in the order of the shapes
+	ws.notes = make([]cellNote, 0)
+	for _, note := range ws.collectNotes() {
+		shapes = append(shapes, note.shape())
+		ws.notes = append(ws.notes, cellNote{note, uint16(len(shapes))})
+	}
+
+	ws.firstImageShape = len(shapes)
+	for _, si := range ws.images {
+		shapes = append(shapes, si.shape())
+	}
+
+	// A chart of a worksheet without data rows is left out
+	if len(ws.Grid) > ws.HeaderRows {
+		for _, chart := range ws.charts {
+			shapes = append(shapes, ws.chartShape(sheetIdx, chart))
+		}
+	}
+
+	return shapes
+}
+
+// collectNotes returns the notes of the cells from the note columns and the notes set by the user, one note per cell
+func (ws *worksheet) collectNotes() []Note {
+	notes := make([]Note, 0)
+	cells := make(map[[2]int]int)
+	add := func(note Note) {
+		cell := [2]int{note.Row, note.Column}
+		if i, ok := cells[cell]; ok {
+			notes[i] = note
+			return
+		}
+		cells[cell] = len(notes)
+		notes = append(notes, note)
+	}
+
+	for rowIdx := ws.HeaderRows; rowIdx < len(ws.Grid); rowIdx++ {
+		row := ws.Grid[rowIdx]
+		for columnIdx := range row {
+			if noteIdx, ok := ws.noteColumns[columnIdx]; ok && noteIdx < len(row) && strings.TrimSpace(row[noteIdx]) != "" {
+				// The text is cut to fit into the TXO record
+				add(Note{rowIdx, columnIdx, truncateUTF16(row[noteIdx], maxNoteLength)})
+			}
+		}
+	}
+	for _, note := range ws.explicitNotes {
+		add(note)
+	}
+
+	return notes
+}
+
+// definedNames returns the built-in names of the worksheet with 0-based index sheetIdx
+func (ws *worksheet) definedNames(sheetIdx int) []definedName {
+	names := make([]definedName, 0)
+	if ws.printArea != nil {
+		names = append(names, definedName{builtinPrintArea, sheetIdx, false, area3d(sheetIdx, *ws.printArea)})
+	}
+	if rgce := ws.printTitles(sheetIdx); rgce != nil {
+		names = append(names, definedName{builtinPrintTitles, sheetIdx, false, rgce})
+	}
+	if r, ok := ws.autoFilterRange(); ok {
+		names = append(names, definedName{builtinFilterDatabase, sheetIdx, true, area3d(sheetIdx, r)})
+	}
+	return names
+}
+
+func (ws *worksheet) getName() string {
+	return ws.Name
+}
+
+func (ws *worksheet) getData(stringCollection *stringCollection) (string, error) {
+	buf := new(bytes.Buffer)
+
+	maxColIdx := ws.maxColumn()
+
+	// The cells go first, the column widths depend on them
+	cellsBuf := new(bytes.Buffer)
+	if err := ws.writeCells(cellsBuf, stringCollection); err != nil {
+		return "", err
+	}
+
+	// Write BOF record
+	ws.storeBof(buf)
+
+	// Write PRINTHEADERS
+	ws.writePrintHeaders(buf)
+
+	// Write PRINTGRIDLINES
+	ws.writePrintGridlines(buf)
+
+	// Write GRIDSET
+	ws.writeGridset(buf)
+
+	columnInfo := make([][]uint16, 0)
+	for i := 0; i <= maxColIdx; i++ {
+		var info = []uint16{uint16(i), uint16(i), uint16(ws.columnWidth(i)), 15, 0, 0}
+		columnInfo = append(columnInfo, info)
+	}
+
+	// Write GUTS
+	ws.writeGuts(buf, columnInfo)
+	// Write DEFAULTROWHEIGHT
+	ws.writeDefaultRowHeight(buf)
+	// Write WSBOOL
+	ws.writeWsbool(buf)
+	// Write horizontal and vertical page breaks
+	ws.writeBreaks(buf)
+	// Write page header
+	ws.writeHeader(buf)
+	// Write page footer
+	ws.writeFooter(buf)
+	// Write page horizontal centering
+	ws.writeHcenter(buf)
+	// Write page vertical centering
+	ws.writeVcenter(buf)
+	// Write left margin
+	ws.writeMarginLeft(buf)
+	// Write right margin
+	ws.writeMarginRight(buf)
+	// Write top margin
+	ws.writeMarginTop(buf)
+	// Write bottom margin
+	ws.writeMarginBottom(buf)
+	// Write page setup
+	ws.writeSetup(buf)
+	// Write sheet protection
+	ws.writeProtect(buf)
+	// Write SCENPROTECT
+	ws.writeScenProtect(buf)
+	// Write OBJECTPROTECT
+	ws.writeObjectProtect(buf)
+	// Write sheet password
+	ws.writePassword(buf)
+	// Write DEFCOLWIDTH record
+	ws.writeDefcol(buf)
+
+	// Write the COLINFO records if they exist
+	if len(columnInfo) != 0 {
+		colcount := len(columnInfo)
+		for i := 0; i < colcount; i++ {
+			ws.writeColinfo(buf, columnInfo[i])
+		}
+	}
+
+	// Write AUTOFILTERINFO record, FILTERMODE is left out as the autofilter has no criteria
+	ws.writeAutoFilterInfo(buf)
+
+	// Write sheet dimensions
+	var firstRowIndex uint32 = 0
+	lastRowIndex := uint32(len(ws.Grid))
+	var firstColumnIndex uint16 = 1
+	var lastColumnIndex uint16 = uint16(maxColIdx) + 1
+
+	ws.writeDimensions(buf, firstRowIndex, lastRowIndex, firstColumnIndex, lastColumnIndex)
+
+	// Write Cells
+	buf.Write(cellsBuf.Bytes())
+
+	// Append
+	ws.writeMsoDrawing(buf)
+
+	// Write NOTE records
+	ws.writeNotes(buf)
+
+	// Write WINDOW2 record
+	ws.writeWindow2(buf)
+
+	// Write PLV record
+	ws.writePageLayoutView(buf)
+
+	// Write ZOOM record
+	ws.writeZoom(buf)
+
+	// Write PANE record
+	ws.writePane(buf)
+
+	// Write SELECTION record
+	ws.writeSelection(buf)
+
+	// Write MergedCellsTable Record
+	ws.writeMergedCells(buf)
+
+	// Write HLINK records
+	ws.writeHyperlinks(buf)
+
+	ws.writeDataValidity(buf)
+	ws.writeSheetLayout(buf)
+
+	// Write SHEETPROTECTION record
+	ws.writeSheetProtection(buf)
+	ws.writeRangeProtection(buf)
+
+	ws.storeEof(buf)
+
+	return buf.String(), nil
+}
+
+// writeCells writes the cell records and measures the auto column widths
+func (ws *worksheet) writeCells(buffer *bytes.Buffer, stringCollection *stringCollection) error {
+	for rowIdx, rows := range ws.Grid {
+		for columnIdx, cValue := range rows {
+			if rowIdx > 65535 || columnIdx > 255 {
+				return ErrTooManyColumns
+			}
+
+			// Write cell value, the header cells are always bold text
+			var cell cellValue
+			var style cellStyle
+			if rowIdx < ws.HeaderRows {
+				cell = cellValue{kind: cellKindString, str: cValue}
+				if cValue == "" {
+					cell.kind = cellKindBlank
+				}
+				style.font = ws.styles.font(fontStyle{bold: true})
+				style.quoted = ws.quoteFormulas && isFormulaLike(ws.parser, cValue)
+			} else if ws.quoteFormulas && isFormulaLike(ws.parser, cValue) {
+				cell = cellValue{kind: cellKindString, str: cValue}
+				style.quoted = true
+			} else {
+				cell = ws.parser.parse(columnIdx, cValue)
+				style.format = ws.styles.numberFormat(cell.format)
+				if target, ok := ws.linkTarget(rows, columnIdx, cell); ok {
+					ws.links = append(ws.links, newHyperlink(rowIdx, columnIdx, target))
+					style.font = ws.styles.font(fontStyle{hyperlink: true})
+				}
+			}
+			style.unlocked, style.hidden = ws.cellProtection(rowIdx, columnIdx)
+			xfIndex := ws.styles.xfIndex(style)
+			if ws.autoWidths != nil {
+				ws.autoWidths[columnIdx] = max(ws.autoWidths[columnIdx], cell.renderedWidth())
+			}
+			switch cell.kind {
+			case cellKindBlank:
+				ws.writeBlank(buffer, rowIdx, columnIdx, xfIndex)
+			case cellKindNumber:
+				ws.writeNumber(buffer, rowIdx, columnIdx, cell.num, xfIndex)
+			case cellKindBoolean:
+				ws.writeBoolErr(buffer, rowIdx, columnIdx, cell.code, false, xfIndex)
+			case cellKindError:
+				ws.writeBoolErr(buffer, rowIdx, columnIdx, cell.code, true, xfIndex)
+			case cellKindFormula:
+				rgce, err := compileFormula(cell.str, ws.sheetNames)
+				if err != nil {
+					return &FormulaError{ws.Name, rowIdx, columnIdx, cValue, err}
+				}
+				ws.writeFormula(buffer, rowIdx, columnIdx, rgce, xfIndex)
+			default:
+				ws.writeString(buffer, rowIdx, columnIdx, cell.str, xfIndex, stringCollection)
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkTarget returns the link target of the cell, from the companion column or the detected URL or email address.
+// The cells with a target longer than Excel accepts stay plain text.
+func (ws *worksheet) linkTarget(row []string, columnIdx int, cell cellValue) (string, bool) {
+	if cell.kind == cellKindBlank {
+		return "", false
+	}
+	if targetIdx, ok := ws.linkColumns[columnIdx]; ok {
+		if targetIdx < len(row) && strings.TrimSpace(row[targetIdx]) != "" && fitsHyperlink(row[targetIdx]) {
+			return row[targetIdx], true
+		}
+		return "", false
+	}
+	if ws.hyperlinks && cell.kind == cellKindString {
+		return detectHyperlink(cell.str)
+	}
+	return "", false
+}
+
+func (ws *worksheet) storeBof(buffer *bytes.Buffer) {
+	var bType uint16 = 0x0010
+
+	var record uint16 = 0x0809 // Record identifier    (BIFF5-BIFF8)
+	var length uint16 = 0x0010
+
+	var build uint16 = 0x0DBB   //    Excel 97
+	var year uint16 = 0x07CC    //    Excel 97
+	var version uint16 = 0x0600 //    BIFF8
+
+	putVar(buffer, record, length, version, bType, build, year)
+
+	// by inspection of real files, MS Office Excel 2007 writes the following
+	putVar(buffer, uint32(0x000100D1), uint32(0x00000406))
+}
+
+func (ws *worksheet) writePrintHeaders(buffer *bytes.Buffer) {
+	var record uint16 = 0x002a // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fPrintRwCol uint16 = 0 // Boolean flag
+
+	putVar(buffer, record, length, fPrintRwCol)
+}
+
+func (ws *worksheet) writePrintGridlines(buffer *bytes.Buffer) {
+	var record uint16 = 0x002b // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fPrintGrid uint16 = 0 // Boolean flag
+
+	putVar(buffer, record, length, fPrintGrid)
+}
+
+func (ws *worksheet) writeGridset(buffer *bytes.Buffer) {
+	var record uint16 = 0x0082 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fGridSet uint16 = 1 // Boolean flag
+
+	putVar(buffer, record, length, fGridSet)
+}
+
+func (ws *worksheet) writeGuts(buffer *bytes.Buffer, columnInfo [][]uint16) {
+	var record uint16 = 0x0080 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	var dxRwGut uint16 = 0x0000  // Size of row gutter
+	var dxColGut uint16 = 0x0000 // Size of col gutter
+
+	// determine maximum row outline level
+	var maxRowOutlineLevel uint16 = 0
+
+	var col_level uint16 = 0
+
+	// Calculate the maximum column outline level. The equivalent calculation
+	// for the row outline level is carried out in writeRow().
+	colcount := len(columnInfo)
+	for i := 0; i < colcount; i++ {
+		col_level = maxUInt16(columnInfo[i][5], col_level)
+	}
+
+	// Set the limits for the outline levels (0 <= x <= 7).
+	col_level = maxUInt16(0, minUInt16(col_level, 7))
+
+	if col_level != 0 {
+		col_level++
+	}
+
+	putVar(buffer, record, length)
+	putVar(buffer, dxRwGut, dxColGut, maxRowOutlineLevel, col_level)
+}
+
+func (ws *worksheet) writeDefaultRowHeight(buffer *bytes.Buffer) {
+	// empty
+}
+
+func (ws *worksheet) writeWsbool(buffer *bytes.Buffer) {
+	var record uint16 = 0x0081 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+	var grbit uint16 = 0x0000
+
+	// Set the option flags
+	grbit |= 0x0001 // Auto page breaks visible
+	grbit |= 0x0040 // Outline summary below
+	grbit |= 0x0080 // Outline summary right
+	if ws.pageSetup.FitToPages {
+		grbit |= 0x0100 // Fit to pages
+	}
+	grbit |= 0x0400 // Outline symbols displayed
+
+	putVar(buffer, record, length, grbit)
+}
+
+func (ws *worksheet) writeBreaks(buffer *bytes.Buffer) {
+	// empty
+}
+
+func (ws *worksheet) writeHeader(buffer *bytes.Buffer) {
+	var record uint16 = 0x0014 // Record identifier
+	recordData := utf8toBIFF8UnicodeLong(ws.header.String())
+	length := uint16(len(recordData))
+
+	putVar(buffer, record, length, []byte(recordData))
+}
+
+func (ws *worksheet) writeFooter(buffer *bytes.Buffer) {
+	var record uint16 = 0x0015 // Record identifier
+	recordData := utf8toBIFF8UnicodeLong(ws.footer.String())
+	length := uint16(len(recordData))
+
+	putVar(buffer, record, length, []byte(recordData))
+}
+
+func (ws *worksheet) writeHcenter(buffer *bytes.Buffer) {
+	var record uint16 = 0x0083 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fHCenter uint16 = 0 // Horizontal centering
+	if ws.pageSetup.CenterHorizontally {
+		fHCenter = 1
+	}
+
+	putVar(buffer, record, length, fHCenter)
+}
+
+func (ws *worksheet) writeVcenter(buffer *bytes.Buffer) {
+	var record uint16 = 0x0084 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fVCenter uint16 = 0 // Vertical centering
+	if ws.pageSetup.CenterVertically {
+		fVCenter = 1
+	}
+
+	putVar(buffer, record, length, fVCenter)
+}
+
+func (ws *worksheet) writeMarginLeft(buffer *bytes.Buffer) {
+	var record uint16 = 0x0026 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	margin := ws.pageSetup.MarginLeft // Margin in inches
+
+	putVar(buffer, record, length, margin)
+}
+
+func (ws *worksheet) writeMarginRight(buffer *bytes.Buffer) {
+	var record uint16 = 0x0027 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	margin := ws.pageSetup.MarginRight // Margin in inches
+
+	putVar(buffer, record, length, margin)
+}
+
+func (ws *worksheet) writeMarginTop(buffer *bytes.Buffer) {
+	var record uint16 = 0x0028 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	margin := ws.pageSetup.MarginTop // Margin in inches
+
+	putVar(buffer, record, length, margin)
+}
+
+func (ws *worksheet) writeMarginBottom(buffer *bytes.Buffer) {
+	var record uint16 = 0x0029 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	margin := ws.pageSetup.MarginBottom // Margin in inches
+
+	putVar(buffer, record, length, margin)
+}
+
+func (ws *worksheet) writeSetup(buffer *bytes.Buffer) {
+	var record uint16 = 0x00A1 // Record identifier
+	var length uint16 = 0x0022 // Number of bytes to follow
+
+	iPaperSize := uint16(ws.pageSetup.PaperSize) // Paper size
+
+	var iScale uint16 = 100 // Print scaling factor
+	if !ws.pageSetup.FitToPages {
+		iScale = uint16(ws.pageSetup.Scale)
+	}
+
+	var iPageStart uint16 = 0x01 // Starting page number
+	var iFitWidth uint16 = 1     // Fit to number of pages wide
+	var iFitHeight uint16 = 1    // Fit to number of pages high
+	if ws.pageSetup.FitToPages {
+		iFitWidth = uint16(ws.pageSetup.FitWidth)
+		iFitHeight = uint16(ws.pageSetup.FitHeight)
+	}
+	var iRes uint16 = 0x0258  // Print resolution
+	var iVRes uint16 = 0x0258 // Vertical print resolution
+
+	numHdr := ws.pageSetup.HeaderMargin // Header Margin
+
+	numFtr := ws.pageSetup.FooterMargin // Footer Margin
+	iCopies := uint16(0x01)             // Number of copies
+
+	var fLeftToRight uint16 = 0x0 // Print over then down
+
+	// Page orientation
+	var fPortrait uint16 = 0x0
+	if ws.pageSetup.Orientation == OrientationPortrait {
+		fPortrait = 0x1
+	}
+
+	var fNoPls uint16 = 0x0    // Setup not read from printer
+	var fNoColor uint16 = 0x0  // Print black and white
+	var fDraft uint16 = 0x0    // Print draft quality
+	var fNotes uint16 = 0x0    // Print notes
+	var fNoOrient uint16 = 0x0 // Orientation not set
+	var fUsePage uint16 = 0x0  // Use custom starting page
+
+	grbit := fLeftToRight
+	grbit |= fPortrait << 1
+	grbit |= fNoPls << 2
+	grbit |= fNoColor << 3
+	grbit |= fDraft << 4
+	grbit |= fNotes << 5
+	grbit |= fNoOrient << 6
+	grbit |= fUsePage << 7
+
+	putVar(buffer, record, length)
+	putVar(buffer, iPaperSize, iScale, iPageStart, iFitWidth, iFitHeight, grbit, iRes, iVRes)
+	putVar(buffer, numHdr, numFtr)
+	putVar(buffer, iCopies)
+}
+
+func (ws *worksheet) writeProtect(buffer *bytes.Buffer) {
+	if ws.protection == nil {
+		return
+	}
+
+	var record uint16 = 0x0012 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	var fLock uint16 = 1 // Worksheet is protected
+
+	putVar(buffer, record, length, fLock)
+}
+
+func (ws *worksheet) writeScenProtect(buffer *bytes.Buffer) {
+	if ws.protection == nil || ws.protection.permissions&AllowEditScenarios != 0 {
+		return
+	}
+
+	var record uint16 = 0x00DD // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	putVar(buffer, record, length, uint16(1))
+}
+
+func (ws *worksheet) writeObjectProtect(buffer *bytes.Buffer) {
+	if ws.protection == nil || ws.protection.permissions&AllowEditObjects != 0 {
+		return
+	}
+
+	var record uint16 = 0x0063 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	putVar(buffer, record, length, uint16(1))
+}
+
+func (ws *worksheet) writePassword(buffer *bytes.Buffer) {
+	if ws.protection == nil || ws.protection.password == "" {
+		return
+	}
+
+	var record uint16 = 0x0013 // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	wPassword := passwordHash(ws.protection.password) // Encoded password
+
+	putVar(buffer, record, length, wPassword)
+}
+
+func (ws *worksheet) writeDefcol(buffer *bytes.Buffer) {
+	var defaultColWidth uint16 = 8
+
+	var record uint16 = 0x0055 // Record identifier
+	var length uint16 = 0x0002 // Number of bytes to follow
+
+	putVar(buffer, record, length, defaultColWidth)
+}
+
+func (ws *worksheet) writeColinfo(buffer *bytes.Buffer, columnInfo []uint16) {
+	var colFirst, colLast, grbit, level uint16
+	var coldx uint16 = 10
+	var xfIndex uint16 = 15
+
+	for i, v := range columnInfo {
+		switch i {
+		case 0:
+			colFirst = v
+			break
+		case 1:
+			colLast = v
+			break
+		case 2:
+			coldx = v
+			break
+		case 3:
+			xfIndex = v
+			break
+		case 4:
+			grbit = v
+			break
+		case 5:
+			level = v
+			break
+		}
+	}
+	var record uint16 = 0x007D // Record identifier
+	var length uint16 = 0x000C // Number of bytes to follow
+
+	coldx *= 256 // Convert to units of 1/256 of a char
+
+	ixfe := xfIndex
+	var reserved uint16 = 0x0000 // Reserved
+
+	level = maxUInt16(0, minUInt16(level, 7))
+	grbit |= level << 8
+
+	putVar(buffer, record, length)
+	putVar(buffer, colFirst, colLast, coldx, ixfe, grbit, reserved)
+}
+
+func (ws *worksheet) writeAutoFilterInfo(buffer *bytes.Buffer) {
+	r, ok := ws.autoFilterRange()
+	if !ok {
+		return
+	}
+
+	var record uint16 = 0x009D // Record identifier
+	var length uint16 = 0x0002 // Bytes to follow
+
+	cEntries := uint16(r.LastColumn - r.FirstColumn + 1) // Number of drop-downs
+
+	putVar(buffer, record, length, cEntries)
+}
+
+func (ws *worksheet) writeDimensions(buffer *bytes.Buffer, firstRowIndex uint32, lastRowIndex uint32, firstColumnIndex uint16, lastColumnIndex uint16) {
+	var record uint16 = 0x0200 // Record identifier
+	var length uint16 = 0x000E
+
+	putVar(buffer, record, length, firstRowIndex, lastRowIndex+1, firstColumnIndex, lastColumnIndex+1, uint16(0x0000))
+}
+
+func (ws *worksheet) writeBlank(buffer *bytes.Buffer, rowIdx int, columnIdx int, xfIndex int) {
+	var record uint16 = 0x0201 // Record identifier
+	var length uint16 = 0x0006 // Number of bytes to follow
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex))
+}
+
+func (ws *worksheet) writeString(buffer *bytes.Buffer, rowIdx int, columnIdx int, cValue string, xfIndex int, stringCollection *stringCollection) {
+	var record uint16 = 0x00FD // Record identifier
+	var length uint16 = 0x000A // Bytes to follow
+
+	strTabVal := stringCollection.addString(cValue)
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex), uint32(strTabVal))
+}
+
+// writeNumber writes an RK record if num fits into the RK format and a NUMBER record otherwise
+func (ws *worksheet) writeNumber(buffer *bytes.Buffer, rowIdx int, columnIdx int, num float64, xfIndex int) {
+	if rk, ok := encodeRK(num); ok {
+		var record uint16 = 0x027E // Record identifier
+		var length uint16 = 0x000A // Bytes to follow
+
+		putVar(buffer, record, length)
+		putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex), rk)
+		return
+	}
+
+	var record uint16 = 0x0203 // Record identifier
+	var length uint16 = 0x000E // Bytes to follow
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex), num)
+}
+
+// writeBoolErr writes a BOOLERR record, value is 0 or 1 for booleans and the error code for errors
+func (ws *worksheet) writeBoolErr(buffer *bytes.Buffer, rowIdx int, columnIdx int, value uint8, isError bool, xfIndex int) {
+	var record uint16 = 0x0205 // Record identifier
+	var length uint16 = 0x0008 // Bytes to follow
+
+	var fError uint8 = 0 // 0 - boolean, 1 - error
+	if isError {
+		fError = 1
+	}
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex), value, fError)
+}
+
+// writeFormula writes a FORMULA record with the compiled formula rgce, Excel calculates the value when it opens the file
+func (ws *worksheet) writeFormula(buffer *bytes.Buffer, rowIdx int, columnIdx int, rgce []byte, xfIndex int) {
+	var record uint16 = 0x0006 // Record identifier
+	length := uint16(0x16 + len(rgce))
+
+	var num float64 = 0         // Current value of the formula
+	var grbit uint16 = 0x0003   // Option flags: fAlwaysCalc, fCalcOnLoad
+	var chn uint32 = 0x00000000 // Reserved
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(rowIdx), uint16(columnIdx), uint16(xfIndex), num, grbit, chn, uint16(len(rgce)), rgce)
+}
+
+func (ws *worksheet) writeMsoDrawing(buffer *bytes.Buffer) {
+	if ws.drawing == nil {
+		return
+	}
+
+	// The images are anchored now, the column widths are known after the cells are written
+	for i, si := range ws.images {
+		ws.drawing.shapes[ws.firstImageShape+i].anchor = ws.imageAnchor(si)
+	}
+	ws.drawing.write(buffer)
+}
+
+func (ws *worksheet) writeWindow2(buffer *bytes.Buffer) {
+	var record uint16 = 0x023E // Record identifier
+	var length uint16 = 0x0012
+
+	var rwTop uint16 = 0x0000   // Top row visible in window
+	var colLeft uint16 = 0x0000 // Leftmost column visible in window
+
+	// The options flags that comprise $grbit
+	var fDspFmla uint16 = 0       // 0 - bit
+	var fDspGrid uint16 = 1       // 1
+	var fDspRwCol uint16 = 1      // 2
+	var fFrozen uint16 = 0        // 3
+	var fDspZeros uint16 = 1      // 4
+	var fDefaultHdr uint16 = 1    // 5
+	var fArabic uint16 = 0        // 6
+	var fDspGuts uint16 = 1       // 7
+	var fFrozenNoSplit uint16 = 0 // 0 - bit
+	if !ws.pane.isEmpty() && !ws.pane.split {
+		fFrozen = 1
+		fFrozenNoSplit = 1
+	}
+	// no support in PhpSpreadsheet for selected sheet, therefore sheet is only selected if it is the active sheet
+	var fSelected uint16 = 1
+	var fPaged uint16 = 1 // 2
+	var fPageBreakPreview uint16 = 0
+
+	grbit := fDspFmla
+	grbit |= fDspGrid << 1
+	grbit |= fDspRwCol << 2
+	grbit |= fFrozen << 3
+	grbit |= fDspZeros << 4
+	grbit |= fDefaultHdr << 5
+	grbit |= fArabic << 6
+	grbit |= fDspGuts << 7
+	grbit |= fFrozenNoSplit << 8
+	grbit |= fSelected << 9
+	grbit |= fPaged << 10
+	grbit |= fPageBreakPreview << 11
+
+	putVar(buffer, record, length)
+	putVar(buffer, grbit, rwTop, colLeft)
+
+	var rgbHdr uint16 = 0x0040 // Row/column heading and gridline color index
+	var zoom_factor_page_break uint16 = 0
+	var zoom_factor_normal uint16 = 100
+
+	putVar(buffer, rgbHdr, uint16(0x0000), zoom_factor_page_break, zoom_factor_normal, uint32(0x00000000))
+}
+
+func (ws *worksheet) writePageLayoutView(buffer *bytes.Buffer) {
+	var record uint16 = 0x088B // Record identifier
+	var length uint16 = 0x0010 // Bytes to follow
+
+	var rt uint16 = 0x088B       // 2
+	var grbitFrt uint16 = 0x0000 // 2
+	var wScalvePLV uint16 = 100  //$this->phpSheet->getSheetView()->getZoomScale(); // 2
+
+	var fPageLayoutView uint16 = 0
+	var fRulerVisible uint16 = 0
+	var fWhitespaceHidden uint16 = 0
+
+	grbit := fPageLayoutView // 2
+	grbit |= fRulerVisible << 1
+	grbit |= fWhitespaceHidden << 3
+
+	putVar(buffer, record, length)
+	putVar(buffer, rt, grbitFrt, uint32(0x00000000), uint32(0x00000000), wScalvePLV, grbit)
+}
+
+func (ws *worksheet) writeZoom(buffer *bytes.Buffer) {
+	// empty
+}
+
+func (ws *worksheet) writePane(buffer *bytes.Buffer) {
+	p := ws.pane
+	if p.isEmpty() {
+		return
+	}
+
+	var record uint16 = 0x0041 // Record identifier
+	var length uint16 = 0x000A // Number of bytes to follow
+
+	// The frozen panes are positioned in rows and columns
+	x := uint16(p.cols)       // Vertical split position
+	y := uint16(p.rows)       // Horizontal split position
+	rwTop := uint16(p.rows)   // Top row visible in the bottom pane
+	colLeft := uint16(p.cols) // Leftmost column visible in the right pane
+
+	// The split panes are positioned in 1/20 of a point, including the row and column headings
+	if p.split {
+		x, y = 0, 0
+		if p.cols > 0 {
+			width := 0
+			for i := 0; i < p.cols; i++ {
+				width += ws.columnWidth(i)
+			}
+			x = uint16(math.Min(113.879*float64(width)+390, 0xFFFF))
+		}
+		if p.rows > 0 {
+			y = uint16(math.Min(20*defaultRowHeight*float64(p.rows)+255, 0xFFFF))
+		}
+	}
+
+	putVar(buffer, record, length)
+	putVar(buffer, x, y, rwTop, colLeft, uint16(p.activePane()))
+}
+
+func (ws *worksheet) writeSelection(buffer *bytes.Buffer) {
+	var record uint16 = 0x001D // Record identifier
+	var length uint16 = 0x000F // Number of bytes to follow
+
+	// Every pane has its own selection, the cursor is placed into the top left cell of the pane
+	for _, pnn := range ws.pane.panes() {
+		rwAct, colAct := ws.pane.firstCell(pnn)
+
+		putVar(buffer, record, length)
+		putVar(buffer, pnn, rwAct, colAct, uint16(0), uint16(1), rwAct, rwAct, uint8(colAct), uint8(colAct))
+	}
+}
+
+func (ws *worksheet) writeMergedCells(buffer *bytes.Buffer) {
+	var record uint16 = 0x00E5 // Record identifier
+
+	// A record takes up to 1027 ranges, the rest goes to the next records
+	for i := 0; i < len(ws.mergedCells); i += maxMergedCellsPerRecord {
+		ranges := ws.mergedCells[i:min(i+maxMergedCellsPerRecord, len(ws.mergedCells))]
+		length := uint16(2 + 8*len(ranges)) // Bytes to follow
+
+		putVar(buffer, record, length, uint16(len(ranges)))
+		for _, r := range ranges {
+			putVar(buffer, uint16(r.FirstRow), uint16(r.LastRow), uint16(r.FirstColumn), uint16(r.LastColumn))
+		}
+	}
+}
+
+func (ws *worksheet) writeNotes(buffer *bytes.Buffer) {
+	for _, note := range ws.notes {
+		note.writeNote(buffer, ws.noteAuthor)
+	}
+}
+
+func (ws *worksheet) writeHyperlinks(buffer *bytes.Buffer) {
+	for _, link := range ws.links {
+		link.write(buffer)
+	}
+}
+
+func (ws *worksheet) writeDataValidity(buffer *bytes.Buffer) {
+	if len(ws.validations) == 0 {
+		return
+	}
+
+	var record uint16 = 0x01B2 // Record identifier
+	var length uint16 = 0x0012 // Bytes to follow
+
+	var grbit uint16 = 0x0000              // Prompt box at default position
+	var horPos uint32 = 0x00000000         // Horizontal position of prompt box, if fixed position
+	var verPos uint32 = 0x00000000         // Vertical position of prompt box, if fixed position
+	var objId uint32 = 0xFFFFFFFF          // Object identifier of drop down arrow object, or -1 if not visible
+	dvCount := uint32(len(ws.validations)) // Number of DV records
+
+	putVar(buffer, record, length)
+	putVar(buffer, grbit, horPos, verPos, objId, dvCount)
+
+	for _, dv := range ws.validations {
+		ws.writeDataValidation(buffer, dv)
+	}
+}
+
+func (ws *worksheet) writeDataValidation(buffer *bytes.Buffer, dv dataValidation) {
+	var record uint16 = 0x01BE // Record identifier
+
+	options := validationTypeCodes[dv.Type]
+	options |= uint32(dv.ErrorStyle) << 4
+	if dv.Type == ValidationList && dv.Value1 == "" {
+		options |= 1 << 7 // Explicit list
+	}
+	if dv.AllowBlank {
+		options |= 1 << 8
+	}
+	if dv.InputTitle != "" || dv.InputMessage != "" {
+		options |= 1 << 18 // Show the input message
+	}
+	options |= 1 << 19 // Show the error message
+	if dv.Type != ValidationList {
+		options |= uint32(dv.Operator) << 20
+	}
+
+	data := new(bytes.Buffer)
+	putVar(data, options)
+	writeDataValidationString(data, dv.InputTitle)
+	writeDataValidationString(data, dv.ErrorTitle)
+	writeDataValidationString(data, dv.InputMessage)
+	writeDataValidationString(data, dv.ErrorMessage)
+	writeDataValidationFormula(data, dv.formula1)
+	writeDataValidationFormula(data, dv.formula2)
+
+	// The cells of the rule
+	r := dv.Range
+	putVar(data, uint16(1), uint16(r.FirstRow), uint16(r.LastRow), uint16(r.FirstColumn), uint16(r.LastColumn))
+
+	putVar(buffer, record, uint16(data.Len()), data.Bytes())
+}
+
+func (ws *worksheet) writeSheetLayout(buffer *bytes.Buffer) {
+	// empty
+}
+
+func (ws *worksheet) writeSheetProtection(buffer *bytes.Buffer) {
+	// record identifier
+	var record uint16 = 0x0867
+	var length uint16 = 23
+
+	// prepare options, a bit is set for every allowed action
+	var options uint16 = 32767
+	if ws.protection != nil {
+		options = uint16(ws.protection.permissions)
+	}
+
+	putVar(buffer, record, length)
+	putVar(buffer, uint16(0x0867), uint32(0x0000), uint32(0x0000), uint8(0x00), uint32(0x01000200), uint32(0xFFFFFFFF), options, uint16(0x0000))
+}
+
+func (ws *worksheet) writeRangeProtection(buffer *bytes.Buffer) {
+	var record uint16 = 0x0868 // Record identifier
+
+	for i, aer := range ws.editRanges {
+		title := allowEditRangeTitle(i, aer)
+
+		data := new(bytes.Buffer)
+		putVar(data, record, uint16(0x0000), uint32(0), uint32(0)) // Future record header
+		putVar(data, uint16(0x0002), uint8(0), uint32(0))          // Shared feature type: protection
+		putVar(data, uint16(1), uint32(0), uint16(0))              // One range, no feature data size
+
+		r := aer.Range
+		putVar(data, uint16(r.FirstRow), uint16(r.LastRow), uint16(r.FirstColumn), uint16(r.LastColumn))
+
+		// The protection without a security descriptor
+		putVar(data, uint32(0), uint32(passwordHash(aer.Password)), []byte(utf8toBIFF8UnicodeLong(title)))
+
+		putVar(buffer, record, uint16(data.Len()), data.Bytes())
+	}
+}
+
+func (ws *worksheet) storeEof(buffer *bytes.Buffer) {
+	var record uint16 = 0x000A // Record identifier
+	var length uint16 = 0x0000 // Number of bytes to follow
+
+	putVar(buffer, record, length)
+}
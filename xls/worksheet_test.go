@@ -0,0 +1,77 @@
+package xls
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestWriteMergedCellsSplit(t *testing.T) {
+	ws := &worksheet{}
+	for i := 0; i < maxMergedCellsPerRecord+1; i++ {
+		ws.mergedCells = append(ws.mergedCells, CellRange{FirstRow: i, FirstColumn: 0, LastRow: i, LastColumn: 1})
+	}
+
+	buffer := new(bytes.Buffer)
+	ws.writeMergedCells(buffer)
+	data := buffer.Bytes()
+
+	row := 0
+	for _, want := range []int{1027, 1} {
+		if len(data) < 6 {
+			t.Fatalf("missing the MERGEDCELLS record of %d ranges", want)
+		}
+		record, length, count := binary.LittleEndian.Uint16(data), int(binary.LittleEndian.Uint16(data[2:])), int(binary.LittleEndian.Uint16(data[4:]))
+		if record != 0x00E5 || count != want || length != 2+8*want {
+			t.Fatalf("record 0x%04X of length %d with %d ranges, want MERGEDCELLS of length %d with %d ranges", record, length, count, 2+8*want, want)
+		}
+
+		// The ranges follow in order: first row, last row, first column, last column
+		for i := 0; i < count; i++ {
+			r := data[6+8*i:]
+			if int(binary.LittleEndian.Uint16(r)) != row || int(binary.LittleEndian.Uint16(r[6:])) != 1 {
+				t.Fatalf("range %d of the record is % x", i, r[:8])
+			}
+			row++
+		}
+		data = data[4+length:]
+	}
+	if len(data) != 0 {
+		t.Errorf("%d bytes after the MERGEDCELLS records", len(data))
+	}
+}
+
+func TestConvertOverlappingMergedCells(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges map[int][]CellRange
+		err    error
+	}{
+		{"adjacent", map[int][]CellRange{AllSheets: {{0, 0, 0, 1}, {0, 2, 0, 3}, {1, 0, 1, 1}}}, nil},
+		{"same worksheet", map[int][]CellRange{0: {{0, 0, 1, 1}, {1, 1, 2, 2}}}, ErrOverlappingMergedCells},
+		{"every worksheet and one worksheet", map[int][]CellRange{AllSheets: {{0, 0, 0, 3}}, 0: {{0, 2, 0, 2}}}, ErrOverlappingMergedCells},
+		{"identical", map[int][]CellRange{AllSheets: {{0, 0, 0, 1}, {0, 0, 0, 1}}}, ErrOverlappingMergedCells},
+		{"other worksheet", map[int][]CellRange{AllSheets: {{0, 0, 0, 3}}, 1: {{0, 2, 0, 2}}}, nil},
+	}
+
+	for _, tt := range tests {
+		converter, err := NewCsv2XlsStreamConverter(";")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for sheet, ranges := range tt.ranges {
+			for _, r := range ranges {
+				converter.WithMergedCells(sheet, r)
+			}
+		}
+
+		err = converter.ConvertStream(context.Background(), strings.NewReader("a;b;c;d\n1;2;3;4\n"), ioutil.Discard)
+		if tt.err == nil && err != nil || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: ConvertStream() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}